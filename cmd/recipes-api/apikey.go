@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/database"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+const apiKeyUsage = "uso: recipes-api apikey create NOME [--role PAPEL] | role ID PAPEL | revoke ID"
+
+// runAPIKeyCommand executa o subcomando "apikey" com os argumentos informados. As chaves
+// criadas sem --role recebem o papel viewer, o de menos permissões; "role" altera o
+// papel de uma chave existente.
+func runAPIKeyCommand(args []string) error {
+	role := models.RoleViewer
+	switch {
+	case len(args) == 4 && args[0] == "create" && args[2] == "--role":
+		role, args = args[3], args[:2]
+	case len(args) == 3 && args[0] == "role":
+		role, args = args[2], args[:2]
+	case len(args) != 2:
+		return errors.New(apiKeyUsage)
+	}
+	if !slices.Contains(models.Roles, role) {
+		return fmt.Errorf("papel inválido: %s, use %s", role, strings.Join(models.Roles, ", "))
+	}
+	//O driver em memória perderia a chave assim que o comando terminasse
+	if GlobalENVConfig.DB_DRIVER == database.DriverMemory {
+		return errors.New("o driver memory não guarda chaves de API entre execuções")
+	}
+
+	ctx := context.Background()
+	apiKeys := database.NewRepositories().APIKeys
+
+	switch args[0] {
+	case "create":
+		key, hash, err := auth.NewAPIKey()
+		if err != nil {
+			return err
+		}
+		id, err := apiKeys.CreateAPIKey(ctx, models.APIKey{Name: args[1], Hash: hash, Role: role})
+		if err != nil {
+			return err
+		}
+		slog.Info("chave de API criada", slog.Int("id", id), slog.String("name", args[1]), slog.String("role", role))
+		//A chave só é exibida agora; o banco guarda apenas o hash dela
+		fmt.Println(key)
+
+	case "role":
+		id, err := strconv.Atoi(args[1])
+		if err != nil || id < 1 {
+			return fmt.Errorf("ID de chave inválido: %s", args[1])
+		}
+		err = apiKeys.SetAPIKeyRole(ctx, id, role)
+		if err != nil {
+			return err
+		}
+		slog.Info("papel da chave de API alterado", slog.Int("id", id), slog.String("role", role))
+
+	case "revoke":
+		id, err := strconv.Atoi(args[1])
+		if err != nil || id < 1 {
+			return fmt.Errorf("ID de chave inválido: %s", args[1])
+		}
+		err = apiKeys.RevokeAPIKey(ctx, id)
+		if err != nil {
+			return err
+		}
+		slog.Info("chave de API revogada", slog.Int("id", id))
+
+	default:
+		return errors.New(apiKeyUsage)
+	}
+
+	return nil
+}
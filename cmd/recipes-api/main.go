@@ -1,21 +1,184 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
 	"github.com/keevferreira/recipes-api/config"
 	"github.com/keevferreira/recipes-api/internal/api"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
 	"github.com/keevferreira/recipes-api/internal/database"
+	"github.com/keevferreira/recipes-api/internal/database/migrate"
+	"github.com/keevferreira/recipes-api/internal/logging"
+	"github.com/keevferreira/recipes-api/internal/metrics"
+	"github.com/keevferreira/recipes-api/internal/ratelimit"
 	"github.com/keevferreira/recipes-api/internal/router"
+	"github.com/keevferreira/recipes-api/internal/tracing"
 )
 
 var GlobalENVConfig *config.Config
 
+// version é a versão do build exibida em /status, definida com -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
 	//Carrega as variáveis do arquivo .env para o OS
 	GlobalENVConfig = config.LoadConfig()
+
+	//Logs estruturados em JSON; o pacote log padrão também passa a escrever por eles
+	level, err := logging.ParseLevel(GlobalENVConfig.LOG_LEVEL)
+	if err != nil {
+		fatal("configuração inválida", err)
+	}
+	slog.SetDefault(logging.New(os.Stdout, level))
+
+	//Traces das requisições, dos repositórios e das consultas SQL
+	sampleRatio, err := config.GetTracingSampleRatio(GlobalENVConfig)
+	if err != nil {
+		fatal("configuração inválida", err)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    GlobalENVConfig.TRACING_EXPORTER,
+		File:        GlobalENVConfig.TRACING_FILE,
+		Endpoint:    GlobalENVConfig.TRACING_OTLP_ENDPOINT,
+		SampleRatio: sampleRatio,
+		ServiceName: "recipes-api",
+		Version:     version,
+	})
+	if err != nil {
+		fatal("erro ao configurar os traces", err)
+	}
+
 	databaseConnectionString := config.GetConnectionString(GlobalENVConfig)
-	database.Connect(databaseConnectionString)
+	err = database.Connect(GlobalENVConfig.DB_DRIVER, databaseConnectionString)
+	if err != nil {
+		fatal("erro ao conectar no banco de dados", err)
+	}
+
+	//Subcomando "migrate": executa as migrações e encerra sem subir o servidor
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrateCommand(os.Args[2:])
+		if err != nil {
+			fatal("erro ao executar as migrações", err)
+		}
+		return
+	}
+
+	//Subcomando "apikey": cria ou revoga chaves de API e encerra sem subir o servidor
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		err := runAPIKeyCommand(os.Args[2:])
+		if err != nil {
+			fatal("erro ao gerenciar as chaves de API", err)
+		}
+		return
+	}
+
+	//Subcomando "user": altera o papel de um usuário e encerra sem subir o servidor
+	if len(os.Args) > 1 && os.Args[1] == "user" {
+		err := runUserCommand(os.Args[2:])
+		if err != nil {
+			fatal("erro ao gerenciar os usuários", err)
+		}
+		return
+	}
+
+	//O driver em memória não tem esquema para migrar
+	if GlobalENVConfig.MIGRATE_ON_STARTUP == "true" && GlobalENVConfig.DB_DRIVER != database.DriverMemory {
+		err := runMigrateCommand([]string{"up"})
+		if err != nil {
+			fatal("erro ao executar as migrações", err)
+		}
+	}
+
+	timeouts, err := config.GetServerTimeouts(GlobalENVConfig)
+	if err != nil {
+		fatal("configuração inválida", err)
+	}
+
+	//O driver em memória não tem migrações para o /readyz conferir
+	var migrator *migrate.Migrator
+	if GlobalENVConfig.DB_DRIVER != database.DriverMemory {
+		migrator, err = database.NewMigrator()
+		if err != nil {
+			fatal("erro ao carregar as migrações", err)
+		}
+	}
+	healthHandler := handlers.NewHealthHandler(GlobalENVConfig.DB_DRIVER, database.DB, migrator, version)
+
+	//Estatísticas do pool de conexões em /metrics; o driver em memória não tem pool
+	if database.DB != nil {
+		err = metrics.RegisterDB(database.DB)
+		if err != nil {
+			fatal("erro ao registrar as métricas do banco de dados", err)
+		}
+	}
+
 	routerControler := router.CreateNewRouter()
-	router.ConfigureRoutes(routerControler)
-	api.InitializeServer(GlobalENVConfig.SERVER_PORT, routerControler)
+	//Os repositórios são instrumentados para medir a duração de cada consulta e abrir um span por chamada
+	repositories := tracing.InstrumentRepositories(metrics.InstrumentRepositories(database.NewRepositories()))
+
+	//Autenticação por token JWT ou chave de API; os tokens dos usuários são emitidos no login
+	tokenTTL, err := config.GetTokenTTL(GlobalENVConfig)
+	if err != nil {
+		fatal("configuração inválida", err)
+	}
+	authenticator, err := auth.NewAuthenticator(auth.Config{
+		HMACSecret:       GlobalENVConfig.AUTH_JWT_SECRET,
+		RSAPublicKeyFile: GlobalENVConfig.AUTH_JWT_PUBLIC_KEY_FILE,
+		JWKSFile:         GlobalENVConfig.AUTH_JWKS_FILE,
+		Issuer:           GlobalENVConfig.AUTH_JWT_ISSUER,
+		Audience:         GlobalENVConfig.AUTH_JWT_AUDIENCE,
+		MaxExternalRole:  GlobalENVConfig.AUTH_JWT_MAX_ROLE,
+		TokenTTL:         tokenTTL,
+	}, repositories.APIKeys, repositories.Users)
+	if err != nil {
+		fatal("erro ao configurar a autenticação", err)
+	}
+	userHandler := handlers.NewUserHandler(repositories.Users, authenticator)
+
+	//Limites de requisições por cliente, guardados na memória desta instância; as credenciais
+	//recusadas e as requisições anteriores à autenticação são contadas no mesmo armazenamento,
+	//por IP
+	rateLimits, err := config.GetRateLimits(GlobalENVConfig)
+	if err != nil {
+		fatal("configuração inválida", err)
+	}
+	rateLimitStore := ratelimit.NewMemoryStore()
+	rateLimitMiddleware := api.RateLimitMiddleware(rateLimitStore, rateLimits)
+	ipRateLimitMiddleware := api.IPRateLimitMiddleware(rateLimitStore, rateLimits.IP)
+	authMiddleware := api.AuthMiddleware(authenticator, GlobalENVConfig.AUTH_ANONYMOUS_READS == "true", rateLimitStore, rateLimits.AuthFailures)
+
+	router.ConfigureRoutes(routerControler, repositories, healthHandler, userHandler, ipRateLimitMiddleware, authMiddleware, rateLimitMiddleware)
+
+	//Bloqueia até o servidor ser encerrado por SIGINT ou SIGTERM
+	serverErr := api.InitializeServer(GlobalENVConfig.SERVER_PORT, routerControler, timeouts)
+	if serverErr != nil {
+		slog.Error("erro no servidor", slog.String("error", serverErr.Error()))
+	}
+
+	//Fecha o pool de conexões só depois que as requisições em andamento terminaram
+	err = database.Disconnect()
+	if err != nil {
+		slog.Error("erro ao desconectar do banco de dados", slog.String("error", err.Error()))
+	}
+
+	//Envia os spans que ainda estão no buffer antes de encerrar
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	err = shutdownTracing(ctx)
+	cancel()
+	if err != nil {
+		slog.Error("erro ao encerrar os traces", slog.String("error", err.Error()))
+	}
+	if serverErr != nil {
+		os.Exit(1)
+	}
+}
 
+// fatal registra o erro e encerra o processo.
+func fatal(message string, err error) {
+	slog.Error(message, slog.String("error", err.Error()))
+	os.Exit(1)
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/keevferreira/recipes-api/internal/database"
+)
+
+const migrateUsage = "uso: recipes-api migrate up | down N | status"
+
+// runMigrateCommand executa o subcomando "migrate" com os argumentos informados.
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, err := database.NewMigrator()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			slog.Info("migração aplicada", slog.Int("version", migration.Version), slog.String("name", migration.Name))
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			slog.Info("nenhuma migração pendente")
+		}
+
+	case "down":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("número de migrações inválido: %s", args[1])
+		}
+
+		reverted, err := migrator.Down(n)
+		for _, migration := range reverted {
+			slog.Info("migração revertida", slog.Int("version", migration.Version), slog.String("name", migration.Name))
+		}
+		if err != nil {
+			return err
+		}
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pendente"
+			if status.Applied {
+				state = "aplicada em " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%03d_%s\t%s\n", status.Version, status.Name, state)
+		}
+
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
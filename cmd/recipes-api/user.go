@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/database"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+const userUsage = "uso: recipes-api user role EMAIL PAPEL"
+
+// runUserCommand executa o subcomando "user" com os argumentos informados. Ele permite
+// promover o primeiro administrador, que não tem como ser promovido pela API.
+func runUserCommand(args []string) error {
+	if len(args) != 3 || args[0] != "role" {
+		return errors.New(userUsage)
+	}
+	//O driver em memória perderia a alteração assim que o comando terminasse
+	if GlobalENVConfig.DB_DRIVER == database.DriverMemory {
+		return errors.New("o driver memory não guarda usuários entre execuções")
+	}
+
+	email, role := strings.ToLower(args[1]), args[2]
+	if !slices.Contains(models.Roles, role) {
+		return fmt.Errorf("papel inválido: %s, use %s", role, strings.Join(models.Roles, ", "))
+	}
+
+	ctx := context.Background()
+	users := database.NewRepositories().Users
+
+	user, err := users.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	err = users.SetUserRole(ctx, user.ID, role)
+	if err != nil {
+		return err
+	}
+	slog.Info("papel do usuário alterado", slog.Int("id", user.ID), slog.String("email", email), slog.String("role", role))
+
+	return nil
+}
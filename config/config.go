@@ -3,16 +3,77 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/ratelimit"
 )
 
 // Config contém as configurações da aplicação
 type Config struct {
 	SERVER_PORT string
+	DB_DRIVER   string
 	DB_NAME     string
 	DB_HOST     string
 	DB_PORT     string
 	DB_USER     string
 	DB_PASSWORD string
+	DB_PATH     string
+	// MIGRATE_ON_STARTUP aplica as migrações pendentes ao iniciar o servidor quando for "true"
+	MIGRATE_ON_STARTUP string
+	// Tempos limite do servidor HTTP, no formato de time.ParseDuration (por exemplo "15s")
+	SERVER_READ_TIMEOUT  string
+	SERVER_WRITE_TIMEOUT string
+	SERVER_IDLE_TIMEOUT  string
+	// SERVER_SHUTDOWN_TIMEOUT é o prazo para concluir as requisições em andamento ao encerrar
+	SERVER_SHUTDOWN_TIMEOUT string
+	// LOG_LEVEL é o nível mínimo dos logs: debug, info, warn ou error
+	LOG_LEVEL string
+	// TRACING_EXPORTER é o destino dos traces: none, stdout ou otlp
+	TRACING_EXPORTER string
+	// TRACING_FILE é o arquivo onde o exportador stdout grava os spans; vazio para a saída padrão
+	TRACING_FILE string
+	// TRACING_OTLP_ENDPOINT é a URL do coletor OTLP/HTTP, como http://localhost:4318
+	TRACING_OTLP_ENDPOINT string
+	// TRACING_SAMPLE_RATIO é a fração dos novos traces registrados, entre 0 e 1
+	TRACING_SAMPLE_RATIO string
+	// AUTH_JWT_SECRET é o segredo que verifica os tokens HS256; vazio os desabilita
+	AUTH_JWT_SECRET string
+	// AUTH_JWT_PUBLIC_KEY_FILE é o arquivo PEM com a chave pública que verifica os tokens RS256
+	AUTH_JWT_PUBLIC_KEY_FILE string
+	// AUTH_JWKS_FILE é um JWKS local com as chaves RSA dos tokens RS256, escolhidas pelo kid
+	AUTH_JWKS_FILE string
+	// AUTH_JWT_ISSUER e AUTH_JWT_AUDIENCE, quando informados, precisam casar com iss e aud
+	AUTH_JWT_ISSUER   string
+	AUTH_JWT_AUDIENCE string
+	// AUTH_JWT_MAX_ROLE é o maior papel que o claim role de um token emitido fora da API concede
+	AUTH_JWT_MAX_ROLE string
+	// AUTH_ANONYMOUS_READS libera as rotas de leitura sem credenciais quando for "true"
+	AUTH_ANONYMOUS_READS string
+	// AUTH_TOKEN_TTL é a validade dos tokens emitidos no login, como "1h"
+	AUTH_TOKEN_TTL string
+	// RATE_LIMIT_READ, RATE_LIMIT_WRITE e RATE_LIMIT_SEARCH são os limites de requisições
+	// de cada cliente por classe, como "60/1m"; "0/1m" remove o limite
+	RATE_LIMIT_READ   string
+	RATE_LIMIT_WRITE  string
+	RATE_LIMIT_SEARCH string
+	// RATE_LIMIT_ROUTES lista as rotas com limite próprio, separadas por vírgulas, como
+	// "POST /users/login=10/1m"
+	RATE_LIMIT_ROUTES string
+	// RATE_LIMIT_AUTH_FAILURES é o limite de credenciais recusadas por IP, como "10/1m"; ao
+	// atingi-lo, o IP fica sem autenticar até o balde voltar a encher
+	RATE_LIMIT_AUTH_FAILURES string
+	// RATE_LIMIT_IP é o limite de requisições por IP conferido antes da autenticação, como
+	// "600/1m", para que a consulta das credenciais no banco também seja limitada
+	RATE_LIMIT_IP string
+}
+
+// ServerTimeouts contém os tempos limite do servidor HTTP já convertidos
+type ServerTimeouts struct {
+	Read     time.Duration
+	Write    time.Duration
+	Idle     time.Duration
+	Shutdown time.Duration
 }
 
 // loadEnvVar lê uma variável de ambiente e a atualiza no Config se não for vazia
@@ -27,27 +88,153 @@ func loadEnvVar(envVarName string, configField *string) {
 func LoadConfig() *Config {
 	// Cria uma configuração padrão
 	config := &Config{
-		SERVER_PORT: "8080",
-		DB_NAME:     "database",
-		DB_HOST:     "localhost",
-		DB_PORT:     "5432",
-		DB_USER:     "user",
-		DB_PASSWORD: "password",
+		SERVER_PORT:        "8080",
+		DB_DRIVER:          "postgres",
+		DB_NAME:            "database",
+		DB_HOST:            "localhost",
+		DB_PORT:            "5432",
+		DB_USER:            "user",
+		DB_PASSWORD:        "password",
+		DB_PATH:            "recipes.db",
+		MIGRATE_ON_STARTUP: "false",
+
+		SERVER_READ_TIMEOUT:     "15s",
+		SERVER_WRITE_TIMEOUT:    "30s",
+		SERVER_IDLE_TIMEOUT:     "60s",
+		SERVER_SHUTDOWN_TIMEOUT: "20s",
+
+		LOG_LEVEL: "info",
+
+		TRACING_EXPORTER:     "none",
+		TRACING_SAMPLE_RATIO: "1",
+
+		AUTH_ANONYMOUS_READS: "false",
+		AUTH_TOKEN_TTL:       "1h",
+		AUTH_JWT_MAX_ROLE:    "author",
+
+		RATE_LIMIT_READ:   "300/1m",
+		RATE_LIMIT_WRITE:  "60/1m",
+		RATE_LIMIT_SEARCH: "30/1m",
+		RATE_LIMIT_ROUTES: "POST /users/login=10/1m",
+
+		RATE_LIMIT_AUTH_FAILURES: "10/1m",
+		RATE_LIMIT_IP:            "600/1m",
 	}
 
 	// Carrega as variáveis de ambiente usando a função loadEnvVar
 	loadEnvVar("SERVER_PORT", &config.SERVER_PORT)
+	loadEnvVar("DB_DRIVER", &config.DB_DRIVER)
 	loadEnvVar("DB_NAME", &config.DB_NAME)
 	loadEnvVar("DB_HOST", &config.DB_HOST)
 	loadEnvVar("DB_PORT", &config.DB_PORT)
 	loadEnvVar("DB_USER", &config.DB_USER)
 	loadEnvVar("DB_PASSWORD", &config.DB_PASSWORD)
+	loadEnvVar("DB_PATH", &config.DB_PATH)
+	loadEnvVar("MIGRATE_ON_STARTUP", &config.MIGRATE_ON_STARTUP)
+	loadEnvVar("SERVER_READ_TIMEOUT", &config.SERVER_READ_TIMEOUT)
+	loadEnvVar("SERVER_WRITE_TIMEOUT", &config.SERVER_WRITE_TIMEOUT)
+	loadEnvVar("SERVER_IDLE_TIMEOUT", &config.SERVER_IDLE_TIMEOUT)
+	loadEnvVar("SERVER_SHUTDOWN_TIMEOUT", &config.SERVER_SHUTDOWN_TIMEOUT)
+	loadEnvVar("LOG_LEVEL", &config.LOG_LEVEL)
+	loadEnvVar("TRACING_EXPORTER", &config.TRACING_EXPORTER)
+	loadEnvVar("TRACING_FILE", &config.TRACING_FILE)
+	loadEnvVar("TRACING_OTLP_ENDPOINT", &config.TRACING_OTLP_ENDPOINT)
+	loadEnvVar("TRACING_SAMPLE_RATIO", &config.TRACING_SAMPLE_RATIO)
+	loadEnvVar("AUTH_JWT_SECRET", &config.AUTH_JWT_SECRET)
+	loadEnvVar("AUTH_JWT_PUBLIC_KEY_FILE", &config.AUTH_JWT_PUBLIC_KEY_FILE)
+	loadEnvVar("AUTH_JWKS_FILE", &config.AUTH_JWKS_FILE)
+	loadEnvVar("AUTH_JWT_ISSUER", &config.AUTH_JWT_ISSUER)
+	loadEnvVar("AUTH_JWT_AUDIENCE", &config.AUTH_JWT_AUDIENCE)
+	loadEnvVar("AUTH_JWT_MAX_ROLE", &config.AUTH_JWT_MAX_ROLE)
+	loadEnvVar("AUTH_ANONYMOUS_READS", &config.AUTH_ANONYMOUS_READS)
+	loadEnvVar("AUTH_TOKEN_TTL", &config.AUTH_TOKEN_TTL)
+	loadEnvVar("RATE_LIMIT_READ", &config.RATE_LIMIT_READ)
+	loadEnvVar("RATE_LIMIT_WRITE", &config.RATE_LIMIT_WRITE)
+	loadEnvVar("RATE_LIMIT_SEARCH", &config.RATE_LIMIT_SEARCH)
+	loadEnvVar("RATE_LIMIT_ROUTES", &config.RATE_LIMIT_ROUTES)
+	loadEnvVar("RATE_LIMIT_AUTH_FAILURES", &config.RATE_LIMIT_AUTH_FAILURES)
+	loadEnvVar("RATE_LIMIT_IP", &config.RATE_LIMIT_IP)
 
 	return config
 }
 
-// GetConnectionString irá gerar a connection string
+// GetConnectionString irá gerar a connection string do driver configurado.
+// Para o SQLite ela é o caminho do arquivo do banco de dados.
 func GetConnectionString(config *Config) string {
+	if config.DB_DRIVER == "sqlite" {
+		return config.DB_PATH
+	}
 	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
 		config.DB_HOST, config.DB_USER, config.DB_PASSWORD, config.DB_NAME, config.DB_PORT)
 }
+
+// GetServerTimeouts converte os tempos limite do servidor HTTP configurados
+func GetServerTimeouts(config *Config) (ServerTimeouts, error) {
+	var timeouts ServerTimeouts
+	fields := []struct {
+		name  string
+		value string
+		field *time.Duration
+	}{
+		{"SERVER_READ_TIMEOUT", config.SERVER_READ_TIMEOUT, &timeouts.Read},
+		{"SERVER_WRITE_TIMEOUT", config.SERVER_WRITE_TIMEOUT, &timeouts.Write},
+		{"SERVER_IDLE_TIMEOUT", config.SERVER_IDLE_TIMEOUT, &timeouts.Idle},
+		{"SERVER_SHUTDOWN_TIMEOUT", config.SERVER_SHUTDOWN_TIMEOUT, &timeouts.Shutdown},
+	}
+	for _, f := range fields {
+		duration, err := time.ParseDuration(f.value)
+		if err != nil || duration <= 0 {
+			return ServerTimeouts{}, fmt.Errorf("valor inválido para %s: %q", f.name, f.value)
+		}
+		*f.field = duration
+	}
+	return timeouts, nil
+}
+
+// GetTracingSampleRatio converte a fração de traces registrados configurada
+func GetTracingSampleRatio(config *Config) (float64, error) {
+	ratio, err := strconv.ParseFloat(config.TRACING_SAMPLE_RATIO, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("valor inválido para TRACING_SAMPLE_RATIO: %q", config.TRACING_SAMPLE_RATIO)
+	}
+	return ratio, nil
+}
+
+// GetTokenTTL converte a validade dos tokens emitidos no login configurada
+func GetTokenTTL(config *Config) (time.Duration, error) {
+	ttl, err := time.ParseDuration(config.AUTH_TOKEN_TTL)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("valor inválido para AUTH_TOKEN_TTL: %q", config.AUTH_TOKEN_TTL)
+	}
+	return ttl, nil
+}
+
+// GetRateLimits converte os limites de requisições configurados
+func GetRateLimits(config *Config) (ratelimit.Limits, error) {
+	var limits ratelimit.Limits
+	fields := []struct {
+		name  string
+		value string
+		field *ratelimit.Limit
+	}{
+		{"RATE_LIMIT_READ", config.RATE_LIMIT_READ, &limits.Read},
+		{"RATE_LIMIT_WRITE", config.RATE_LIMIT_WRITE, &limits.Write},
+		{"RATE_LIMIT_SEARCH", config.RATE_LIMIT_SEARCH, &limits.Search},
+		{"RATE_LIMIT_AUTH_FAILURES", config.RATE_LIMIT_AUTH_FAILURES, &limits.AuthFailures},
+		{"RATE_LIMIT_IP", config.RATE_LIMIT_IP, &limits.IP},
+	}
+	for _, f := range fields {
+		limit, err := ratelimit.ParseLimit(f.value)
+		if err != nil {
+			return ratelimit.Limits{}, fmt.Errorf("valor inválido para %s: %w", f.name, err)
+		}
+		*f.field = limit
+	}
+
+	routes, err := ratelimit.ParseRouteLimits(config.RATE_LIMIT_ROUTES)
+	if err != nil {
+		return ratelimit.Limits{}, fmt.Errorf("valor inválido para RATE_LIMIT_ROUTES: %w", err)
+	}
+	limits.Routes = routes
+	return limits, nil
+}
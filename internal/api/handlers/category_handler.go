@@ -4,39 +4,42 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"github.com/gorilla/mux"
 	"github.com/keevferreira/recipes-api/internal/models"
-	"github.com/keevferreira/recipes-api/internal/utils"
+	"github.com/keevferreira/recipes-api/internal/validation"
 )
 
 // CategoryHandler é uma estrutura para manipulação de categorias de receitas.
 type CategoryHandler struct {
-	// Aqui você pode incluir dependências, como um serviço de banco de dados.
-	// Por simplicidade, vamos apenas lidar com manipulação de receitas diretamente.
+	categories models.CategoryRepository
 }
 
-// NewCategoryHandler cria uma nova instância de CategoryHandler.
-func NewCategoryHandler() *CategoryHandler {
-	return &CategoryHandler{}
+// NewCategoryHandler cria uma nova instância de CategoryHandler que usa o repositório informado.
+func NewCategoryHandler(categories models.CategoryRepository) *CategoryHandler {
+	return &CategoryHandler{categories: categories}
 }
 
 // CreateCategory cria uma nova receita.
 func (ch *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
 	// Decodifica o corpo da solicitação em um objeto Category
 	var category models.Category
-	err := json.NewDecoder(r.Body).Decode(&category)
+	err := decodeJSON(r, &category)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	// Aqui você pode validar a entrada, como garantir que campos obrigatórios estejam presentes, etc.
+	// Valida a entrada antes de salvá-la, listando todos os campos inválidos
+	err = validation.Category.Validate(category)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
 	// Salve a receita no banco de dados ou onde quer que você esteja armazenando.
 	// Suponha que haja uma função SaveRecipe no modelo de dados que manipula a persistência.
-	_, err = models.CreateCategory(category)
+	_, err = ch.categories.CreateCategory(r.Context(), category)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -44,38 +47,43 @@ func (ch *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request
 	w.WriteHeader(http.StatusCreated)
 }
 
-// GetCategories recupera todas as categorias de receitas.
+// GetCategories recupera uma página das categorias de receitas, com ordenação opcional.
 func (ch *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	options, err := listOptionsFromQuery(r, models.CategorySortKeys...)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+
 	// Recupere as categorias de receitas do banco de dados ou de onde quer que você esteja armazenando.
-	recipes, err := models.GetAllCategories()
+	categories, info, err := ch.categories.GetAllCategories(r.Context(), options)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	ids := make([]int, 0, len(categories))
+	for _, category := range categories {
+		ids = append(ids, category.ID)
+	}
+	if categories == nil {
+		categories = []models.Category{}
+	}
+
 	// Serializa as categorias de receitas para JSON e envia a resposta.
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(recipes)
+	json.NewEncoder(w).Encode(newPageResponse(r, categories, ids, options, info))
 }
 
 func (ch *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID da categoria dos parâmetros da URL
-	categoryID := mux.Vars(r)["id"]
-
-	// Converte o ID da categoria de string para inteiro
-	id := utils.StringToInt(categoryID)
+	categoryID := pathID(r, "id")
 
 	// Aqui, estamos simulando a busca de uma categoria em um banco de dados.
-	category, err := models.GetCategoryByID(id)
+	category, err := ch.categories.GetCategoryByID(r.Context(), categoryID)
 	if err != nil {
-		// Se ocorrer um erro ao buscar a categoria, retorna um erro interno do servidor
-		http.Error(w, "Erro ao buscar a categoria", http.StatusInternalServerError)
-		return
-	}
-
-	// Se a categoria não for encontrada, retorna um erro de não encontrado
-	if category.ID == 0 {
-		http.Error(w, "Categoria não encontrada", http.StatusNotFound)
+		// Se ocorrer um erro ao buscar a categoria, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
@@ -86,23 +94,30 @@ func (ch *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Reques
 
 func (ch *CategoryHandler) UpdateCategoryByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID da categoria dos parâmetros da URL
-	categoryID := mux.Vars(r)["id"]
+	categoryID := pathID(r, "id")
 
 	// Decodifica o corpo da solicitação em um objeto Category
 	var updatedCategory models.Category
-	err := json.NewDecoder(r.Body).Decode(&updatedCategory)
+	err := decodeJSON(r, &updatedCategory)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Valida a entrada antes de salvá-la, listando todos os campos inválidos
+	err = validation.Category.Validate(updatedCategory)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar o corpo da solicitação", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
 	// Supondo que você tenha uma função que atualize a categoria com base no ID
 	// Aqui, estamos simulando a atualização de uma categoria em um banco de dados.
 	// Você precisaria implementar essa função de acordo com sua lógica de negócios e banco de dados.
-	err = models.UpdateCategoryByID(utils.StringToInt(categoryID), updatedCategory)
+	err = ch.categories.UpdateCategoryByID(r.Context(), categoryID, updatedCategory)
 	if err != nil {
-		// Se ocorrer um erro ao atualizar a categoria, retorna um erro interno do servidor
-		http.Error(w, "Erro ao atualizar a categoria", http.StatusInternalServerError)
+		// Se ocorrer um erro ao atualizar a categoria, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
@@ -113,15 +128,15 @@ func (ch *CategoryHandler) UpdateCategoryByID(w http.ResponseWriter, r *http.Req
 
 func (ch *CategoryHandler) DeleteCategoryByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID da categoria dos parâmetros da URL
-	categoryID := mux.Vars(r)["id"]
+	categoryID := pathID(r, "id")
 
 	// Supondo que você tenha uma função que delete a categoria com base no ID
 	// Aqui, estamos simulando a exclusão de uma categoria em um banco de dados.
 	// Você precisaria implementar essa função de acordo com sua lógica de negócios e banco de dados.
-	err := models.DeleteCategoryByID(utils.StringToInt(categoryID))
+	err := ch.categories.DeleteCategoryByID(r.Context(), categoryID)
 	if err != nil {
-		// Se ocorrer um erro ao deletar a categoria, retorna um erro interno do servidor
-		http.Error(w, "Erro ao deletar a categoria", http.StatusInternalServerError)
+		// Se ocorrer um erro ao deletar a categoria, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
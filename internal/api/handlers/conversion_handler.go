@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+	"github.com/keevferreira/recipes-api/internal/units"
+)
+
+// ConversionHandler é uma estrutura para conversão de unidades de medida.
+type ConversionHandler struct {
+	ingredients models.IngredientRepository
+}
+
+// NewConversionHandler cria uma nova instância de ConversionHandler que usa o
+// repositório de ingredientes para obter a densidade dos ingredientes.
+func NewConversionHandler(ingredients models.IngredientRepository) *ConversionHandler {
+	return &ConversionHandler{ingredients: ingredients}
+}
+
+// conversionRequest é o corpo de uma requisição de conversão. Conversões entre
+// volume e massa usam a densidade informada ou a do ingrediente informado.
+type conversionRequest struct {
+	Quantity     float64  `json:"quantity"`
+	From         string   `json:"from"`
+	To           string   `json:"to"`
+	IngredientID int      `json:"ingredient_id,omitempty"`
+	Density      *float64 `json:"density,omitempty"`
+}
+
+// conversionResponse é o resultado de uma conversão.
+type conversionResponse struct {
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+// Convert converte uma quantidade entre duas unidades de medida.
+func (ch *ConversionHandler) Convert(w http.ResponseWriter, r *http.Request) {
+	// Decodifica o corpo da solicitação em um objeto conversionRequest
+	var request conversionRequest
+	err := decodeJSON(r, &request)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Usa a densidade informada ou, se não houver, a do ingrediente
+	var density float64
+	switch {
+	case request.Density != nil:
+		density = *request.Density
+	case request.IngredientID != 0:
+		ingredient, err := ch.ingredients.GetIngredientByID(r.Context(), request.IngredientID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if ingredient.Density != nil {
+			density = *ingredient.Density
+		}
+	}
+
+	quantity, err := units.ConvertWithDensity(request.Quantity, request.From, request.To, density)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+
+	// Retorna a quantidade convertida, na grafia canônica da unidade de destino
+	unit, _ := units.Lookup(request.To)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conversionResponse{Quantity: quantity, Unit: unit.Symbol})
+}
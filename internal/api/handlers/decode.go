@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// malformedBodyError indica um corpo de requisição que não é um JSON válido.
+type malformedBodyError struct {
+	err error
+}
+
+func (e *malformedBodyError) Error() string {
+	return "Erro ao decodificar o corpo da solicitação: " + e.err.Error()
+}
+
+// decodeJSON decodifica o corpo JSON da requisição em v, recusando campos desconhecidos.
+// Campos desconhecidos ou com o tipo errado viram um erro de validação; um corpo
+// malformado vira um malformedBodyError. Os dois são tratados por writeError.
+func decodeJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err == nil {
+		return nil
+	}
+
+	// O pacote encoding/json não tem um tipo para campos desconhecidos, apenas a mensagem
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		name, unquoteErr := strconv.Unquote(field)
+		if unquoteErr != nil {
+			name = field
+		}
+		return models.NewFieldError(name, models.FieldUnknown, "is not a known field")
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return models.NewFieldError(typeErr.Field, models.FieldInvalid, "must be a JSON %s", jsonType(typeErr.Type.Kind().String()))
+	}
+
+	return &malformedBodyError{err: err}
+}
+
+// jsonType traduz o tipo Go de um campo para o tipo JSON esperado.
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "number"
+	case kind == "bool":
+		return "boolean"
+	case kind == "slice", kind == "array":
+		return "array"
+	case kind == "struct", kind == "map":
+		return "object"
+	default:
+		return kind
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/database/migrate"
+	"github.com/keevferreira/recipes-api/internal/logging"
+)
+
+// pingTimeout é o tempo máximo de espera pela resposta do banco de dados nas verificações.
+const pingTimeout = 2 * time.Second
+
+// HealthHandler é uma estrutura para os endpoints de saúde do serviço.
+type HealthHandler struct {
+	driver string
+	// db é nil para o driver em memória, que não tem conexão nem migrações.
+	db       *sql.DB
+	migrator *migrate.Migrator
+	version  string
+	started  time.Time
+}
+
+// NewHealthHandler cria uma nova instância de HealthHandler para o banco de dados e as
+// migrações informados. version é a versão do build exibida em /status.
+func NewHealthHandler(driver string, db *sql.DB, migrator *migrate.Migrator, version string) *HealthHandler {
+	return &HealthHandler{driver: driver, db: db, migrator: migrator, version: version, started: time.Now()}
+}
+
+// check é o resultado de uma verificação de dependência. O motivo de uma falha vai só
+// para o log, para não expor detalhes do banco de dados a quem consulta os endpoints.
+type check struct {
+	Status string `json:"status"`
+}
+
+// migrationsCheck é o resultado da verificação das migrações.
+type migrationsCheck struct {
+	check
+	Version  int `json:"version"`
+	Expected int `json:"expected"`
+}
+
+// readiness é a resposta de /readyz.
+type readiness struct {
+	Status     string           `json:"status"`
+	Database   check            `json:"database"`
+	Migrations *migrationsCheck `json:"migrations,omitempty"`
+}
+
+// Healthz responde 200 enquanto o processo estiver de pé, sem consultar dependências.
+func (hh *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// Readyz responde 200 se o banco de dados responde e as migrações estão na versão
+// esperada pelo binário, e 503 caso contrário.
+func (hh *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	response := hh.readiness(r.Context())
+
+	status := http.StatusOK
+	if response.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// poolStats são as estatísticas do pool de conexões exibidas em /status.
+type poolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+}
+
+// statusResponse é a resposta de /status.
+type statusResponse struct {
+	readiness
+	Version       string     `json:"version"`
+	GoVersion     string     `json:"go_version"`
+	Driver        string     `json:"driver"`
+	StartedAt     time.Time  `json:"started_at"`
+	Uptime        string     `json:"uptime"`
+	UptimeSeconds int64      `json:"uptime_seconds"`
+	Pool          *poolStats `json:"pool,omitempty"`
+}
+
+// Status retorna um relatório detalhado do serviço: versão, tempo no ar, verificações
+// de prontidão e estatísticas do pool de conexões. Responde sempre 200 e é restrito aos
+// administradores.
+func (hh *HealthHandler) Status(w http.ResponseWriter, r *http.Request) {
+	uptime := time.Since(hh.started)
+	response := statusResponse{
+		readiness:     hh.readiness(r.Context()),
+		Version:       hh.version,
+		GoVersion:     runtime.Version(),
+		Driver:        hh.driver,
+		StartedAt:     hh.started,
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+	}
+
+	if hh.db != nil {
+		stats := hh.db.Stats()
+		response.Pool = &poolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDuration:       stats.WaitDuration.String(),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// readiness executa as verificações do banco de dados e das migrações.
+func (hh *HealthHandler) readiness(ctx context.Context) readiness {
+	response := readiness{Status: "ok", Database: check{Status: "ok"}}
+	if hh.db == nil {
+		return response
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	logger := logging.FromContext(ctx)
+
+	err := hh.db.PingContext(ctx)
+	if err != nil {
+		logger.Error("banco de dados indisponível", slog.String("error", err.Error()))
+		response.Status = "unavailable"
+		response.Database = check{Status: "unavailable"}
+		return response
+	}
+
+	if hh.migrator == nil {
+		return response
+	}
+
+	migrations := &migrationsCheck{check: check{Status: "ok"}, Expected: hh.migrator.Latest()}
+	migrations.Version, err = hh.migrator.Version()
+	switch {
+	case err != nil:
+		logger.Error("erro ao ler a versão das migrações", slog.String("error", err.Error()))
+		migrations.check = check{Status: "unavailable"}
+	case migrations.Version != migrations.Expected:
+		logger.Warn("migrações pendentes", slog.Int("version", migrations.Version), slog.Int("expected", migrations.Expected))
+		migrations.check = check{Status: "unavailable"}
+	}
+	if migrations.Status != "ok" {
+		response.Status = "unavailable"
+	}
+	response.Migrations = migrations
+
+	return response
+}
@@ -4,39 +4,42 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"github.com/gorilla/mux"
 	"github.com/keevferreira/recipes-api/internal/models"
-	"github.com/keevferreira/recipes-api/internal/utils"
+	"github.com/keevferreira/recipes-api/internal/validation"
 )
 
-// RecipeHandler é uma estrutura para manipulação de ingredientes.
+// IngredientHandler é uma estrutura para manipulação de ingredientes.
 type IngredientHandler struct {
-	// Aqui você pode incluir dependências, como um serviço de banco de dados.
-	// Por simplicidade, vamos apenas lidar com manipulação de ingredientes diretamente.
+	ingredients models.IngredientRepository
 }
 
-// NewIngredientHandler cria uma nova instância de IngredientHandler.
-func NewIngredientHandler() *IngredientHandler {
-	return &IngredientHandler{}
+// NewIngredientHandler cria uma nova instância de IngredientHandler que usa o repositório informado.
+func NewIngredientHandler(ingredients models.IngredientRepository) *IngredientHandler {
+	return &IngredientHandler{ingredients: ingredients}
 }
 
 // CreateIngredient cria uma nova receita.
 func (ih *IngredientHandler) CreateIngredient(w http.ResponseWriter, r *http.Request) {
 	// Decodifica o corpo da solicitação em um objeto Ingredient
 	var ingredient models.Ingredient
-	err := json.NewDecoder(r.Body).Decode(&ingredient)
+	err := decodeJSON(r, &ingredient)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	// Aqui você pode validar a entrada, como garantir que campos obrigatórios estejam presentes, etc.
+	// Valida a entrada antes de salvá-la, listando todos os campos inválidos
+	err = validation.Ingredient.Validate(ingredient)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
 	// Salve a receita no banco de dados ou onde quer que você esteja armazenando.
 	// Suponha que haja uma função SaveRecipe no modelo de dados que manipula a persistência.
-	_, err = models.CreateIngredient(ingredient)
+	_, err = ih.ingredients.CreateIngredient(r.Context(), ingredient)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -44,38 +47,44 @@ func (ih *IngredientHandler) CreateIngredient(w http.ResponseWriter, r *http.Req
 	w.WriteHeader(http.StatusCreated)
 }
 
-// GetIngredients recupera todos os ingredientes.
+// GetIngredients recupera uma página dos ingredientes, com filtro por corredor e ordenação opcionais.
 func (ih *IngredientHandler) GetIngredients(w http.ResponseWriter, r *http.Request) {
+	options, err := listOptionsFromQuery(r, models.IngredientSortKeys...)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	filter := models.IngredientFilter{Aisle: r.URL.Query().Get("aisle")}
+
 	// Recupere os ingredientes do banco de dados ou de onde quer que você esteja armazenando.
-	ingredients, err := models.GetAllIngredients()
+	ingredients, info, err := ih.ingredients.GetAllIngredients(r.Context(), filter, options)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	// Serializa as receitas para JSON e envia a resposta.
+	ids := make([]int, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		ids = append(ids, ingredient.ID)
+	}
+	if ingredients == nil {
+		ingredients = []models.Ingredient{}
+	}
+
+	// Serializa os ingredientes para JSON e envia a resposta.
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(ingredients)
+	json.NewEncoder(w).Encode(newPageResponse(r, ingredients, ids, options, info))
 }
 
 func (ih *IngredientHandler) GetIngredientByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID do ingrediente dos parâmetros da URL
-	ingredientID := mux.Vars(r)["id"]
-
-	// Converte o ID do ingrediente de string para inteiro
-	id := utils.StringToInt(ingredientID)
+	ingredientID := pathID(r, "id")
 
 	// Aqui, estamos simulando a busca de um ingrediente em um banco de dados.
-	ingredient, err := models.GetIngredientByID(id)
+	ingredient, err := ih.ingredients.GetIngredientByID(r.Context(), ingredientID)
 	if err != nil {
-		// Se ocorrer um erro ao buscar o ingrediente, retorna um erro interno do servidor
-		http.Error(w, "Erro ao buscar o ingrediente", http.StatusInternalServerError)
-		return
-	}
-
-	// Se o ingrediente não for encontrado, retorna um erro de não encontrado
-	if ingredient.ID == 0 {
-		http.Error(w, "Ingrediente não encontrado", http.StatusNotFound)
+		// Se ocorrer um erro ao buscar o ingrediente, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
@@ -86,23 +95,30 @@ func (ih *IngredientHandler) GetIngredientByID(w http.ResponseWriter, r *http.Re
 
 func (ih *IngredientHandler) UpdateIngredientByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID do ingrediente dos parâmetros da URL
-	ingredientID := mux.Vars(r)["id"]
+	ingredientID := pathID(r, "id")
 
 	// Decodifica o corpo da solicitação em um objeto Ingredient
 	var updatedIngredient models.Ingredient
-	err := json.NewDecoder(r.Body).Decode(&updatedIngredient)
+	err := decodeJSON(r, &updatedIngredient)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Valida a entrada antes de salvá-la, listando todos os campos inválidos
+	err = validation.Ingredient.Validate(updatedIngredient)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar o corpo da solicitação", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
 	// Supondo que você tenha uma função que atualize o ingrediente com base no ID
 	// Aqui, estamos simulando a atualização de um ingrediente em um banco de dados.
 	// Você precisaria implementar essa função de acordo com sua lógica de negócios e banco de dados.
-	err = models.UpdateIngredientByID(utils.StringToInt(ingredientID), updatedIngredient)
+	err = ih.ingredients.UpdateIngredientByID(r.Context(), ingredientID, updatedIngredient)
 	if err != nil {
-		// Se ocorrer um erro ao atualizar o ingrediente, retorna um erro interno do servidor
-		http.Error(w, "Erro ao atualizar o ingrediente", http.StatusInternalServerError)
+		// Se ocorrer um erro ao atualizar o ingrediente, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
@@ -111,17 +127,17 @@ func (ih *IngredientHandler) UpdateIngredientByID(w http.ResponseWriter, r *http
 	json.NewEncoder(w).Encode(updatedIngredient)
 }
 
-func (ri *IngredientHandler) DeleteIngredientByID(w http.ResponseWriter, r *http.Request) {
+func (ih *IngredientHandler) DeleteIngredientByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID do ingrediente dos parâmetros da URL
-	ingredientID := mux.Vars(r)["id"]
+	ingredientID := pathID(r, "id")
 
 	// Supondo que você tenha uma função que delete o ingrediente com base no ID
 	// Aqui, estamos simulando a exclusão de um ingrediente em um banco de dados.
 	// Você precisaria implementar essa função de acordo com sua lógica de negócios e banco de dados.
-	err := models.DeleteIngredientByID(utils.StringToInt(ingredientID))
+	err := ih.ingredients.DeleteIngredientByID(r.Context(), ingredientID)
 	if err != nil {
-		// Se ocorrer um erro ao deletar o ingrediente, retorna um erro interno do servidor
-		http.Error(w, "Erro ao deletar o ingredinte", http.StatusInternalServerError)
+		// Se ocorrer um erro ao deletar o ingrediente, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
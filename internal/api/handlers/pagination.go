@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+const (
+	// defaultPageLimit é o tamanho padrão das páginas das coleções.
+	defaultPageLimit = 20
+	// maxPageLimit é o maior tamanho de página aceito.
+	maxPageLimit = 100
+)
+
+// pageResponse é uma página de uma coleção com os links para as páginas vizinhas.
+type pageResponse struct {
+	Items      any    `json:"items"`
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// listOptionsFromQuery lê os parâmetros limit, offset, cursor e sort da query string.
+// O sort aceita uma das chaves informadas, com o prefixo "-" para ordem decrescente.
+func listOptionsFromQuery(r *http.Request, sortKeys ...string) (models.ListOptions, error) {
+	query := r.URL.Query()
+
+	limit, err := intFromQuery(r, "limit", defaultPageLimit)
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		return models.ListOptions{}, errors.New("parâmetro limit inválido, use um valor entre 1 e " + strconv.Itoa(maxPageLimit))
+	}
+
+	offset, err := intFromQuery(r, "offset", 0)
+	if err != nil || offset < 0 {
+		return models.ListOptions{}, errors.New("parâmetro offset inválido")
+	}
+
+	options := models.ListOptions{Limit: limit, Offset: offset, Sort: models.SortByID}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := models.ParseCursor(value)
+		if err != nil {
+			return models.ListOptions{}, errors.New("parâmetro cursor inválido")
+		}
+		options.Cursor = &cursor
+		options.Offset = 0
+	}
+
+	if value := query.Get("sort"); value != "" {
+		options.Sort, options.Desc = strings.CutPrefix(value, "-")
+		if err := options.CheckSort(sortKeys...); err != nil {
+			return models.ListOptions{}, err
+		}
+	}
+
+	return options, nil
+}
+
+// newPageResponse monta a resposta de uma página. ids são os IDs dos itens, na ordem
+// da página, usados para gerar os cursores.
+func newPageResponse(r *http.Request, items any, ids []int, options models.ListOptions, info models.PageInfo) pageResponse {
+	response := pageResponse{
+		Items:  items,
+		Total:  info.Total,
+		Limit:  options.Limit,
+		Offset: options.Offset,
+	}
+
+	if len(ids) > 0 {
+		if info.HasNext {
+			response.NextCursor = models.Cursor{ID: ids[len(ids)-1]}.Encode()
+		}
+		if info.HasPrev {
+			response.PrevCursor = models.Cursor{ID: ids[0], Before: true}.Encode()
+		}
+	}
+
+	// Os links seguem o modo de paginação usado na requisição
+	if options.Cursor != nil {
+		if response.NextCursor != "" {
+			response.Next = pageLink(r, "cursor", response.NextCursor)
+		}
+		if response.PrevCursor != "" {
+			response.Prev = pageLink(r, "cursor", response.PrevCursor)
+		}
+		return response
+	}
+
+	if info.HasNext {
+		response.Next = pageLink(r, "offset", strconv.Itoa(options.Offset+options.Limit))
+	}
+	if info.HasPrev {
+		response.Prev = pageLink(r, "offset", strconv.Itoa(max(options.Offset-options.Limit, 0)))
+	}
+
+	return response
+}
+
+// pageLink copia a URL da requisição trocando o parâmetro de paginação.
+func pageLink(r *http.Request, name string, value string) string {
+	query := r.URL.Query()
+	query.Del("cursor")
+	query.Del("offset")
+	query.Set(name, value)
+
+	link := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return link.String()
+}
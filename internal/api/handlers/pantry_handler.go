@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+const (
+	// defaultMaxMissing é o número padrão de ingredientes faltantes aceito nas receitas quase possíveis.
+	defaultMaxMissing = 2
+	// defaultCookableLimit é o número padrão de receitas retornadas pela busca.
+	defaultCookableLimit = 50
+)
+
+// PantryHandler é uma estrutura para manipulação da despensa.
+type PantryHandler struct {
+	pantry models.PantryRepository
+}
+
+// NewPantryHandler cria uma nova instância de PantryHandler que usa o repositório informado.
+func NewPantryHandler(pantry models.PantryRepository) *PantryHandler {
+	return &PantryHandler{pantry: pantry}
+}
+
+// pantryItemRequest é o corpo da requisição que adiciona um ingrediente à despensa.
+type pantryItemRequest struct {
+	// Quantity é opcional; sem ela o ingrediente é considerado suficiente para qualquer receita.
+	Quantity *float64 `json:"quantity"`
+	Unit     string   `json:"unit"`
+}
+
+// cookableResponse separa as receitas que podem ser feitas das que estão quase lá.
+type cookableResponse struct {
+	Cookable   []models.CookableRecipe `json:"cookable"`
+	NearMisses []models.CookableRecipe `json:"near_misses"`
+}
+
+// GetPantryItems recupera todos os ingredientes da despensa de quem fez a requisição.
+func (ph *PantryHandler) GetPantryItems(w http.ResponseWriter, r *http.Request) {
+	items, err := ph.pantry.GetPantryItems(r.Context(), pantryOwner(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna os itens como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// SetPantryItem adiciona um ingrediente à despensa de quem fez a requisição ou atualiza
+// sua quantidade.
+func (ph *PantryHandler) SetPantryItem(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID do ingrediente dos parâmetros da URL
+	ingredientID := pathID(r, "ingredient_id")
+
+	// Decodifica o corpo da solicitação em um objeto pantryItemRequest
+	var request pantryItemRequest
+	err := decodeJSON(r, &request)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if request.Quantity != nil && *request.Quantity < 0 {
+		badRequest(w, r, "A quantidade não pode ser negativa")
+		return
+	}
+
+	item := models.PantryItem{
+		IngredientID: ingredientID,
+		Quantity:     request.Quantity,
+		Unit:         request.Unit,
+	}
+	err = ph.pantry.SetPantryItem(r.Context(), pantryOwner(r), item)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Se o ingrediente foi salvo com sucesso, retorne um status OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeletePantryItem remove um ingrediente da despensa de quem fez a requisição.
+func (ph *PantryHandler) DeletePantryItem(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID do ingrediente dos parâmetros da URL
+	ingredientID := pathID(r, "ingredient_id")
+
+	err := ph.pantry.DeletePantryItem(r.Context(), pantryOwner(r), ingredientID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Se o ingrediente foi removido com sucesso, retorne um status OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetCookableRecipes busca as receitas que podem ser feitas com a despensa de quem fez
+// a requisição e as que estão a poucos ingredientes de distância.
+func (ph *PantryHandler) GetCookableRecipes(w http.ResponseWriter, r *http.Request) {
+	maxMissing, err := intFromQuery(r, "max_missing", defaultMaxMissing)
+	if err != nil || maxMissing < 0 {
+		badRequest(w, r, "Parâmetro max_missing inválido")
+		return
+	}
+	limit, err := intFromQuery(r, "limit", defaultCookableLimit)
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		badRequest(w, r, "Parâmetro limit inválido, use um valor entre 1 e "+strconv.Itoa(maxPageLimit))
+		return
+	}
+
+	recipes, err := ph.pantry.FindCookableRecipes(r.Context(), pantryOwner(r), maxMissing, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	response := cookableResponse{
+		Cookable:   []models.CookableRecipe{},
+		NearMisses: []models.CookableRecipe{},
+	}
+	for _, recipe := range recipes {
+		if len(recipe.Missing) == 0 {
+			response.Cookable = append(response.Cookable, recipe)
+		} else {
+			response.NearMisses = append(response.NearMisses, recipe)
+		}
+	}
+
+	// Retorna as receitas como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// intFromQuery lê um parâmetro inteiro da query string, usando o valor padrão quando ele não é informado.
+func intFromQuery(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+// pantryOwner identifica a despensa de quem fez a requisição. Cada principal tem a
+// sua; a do anônimo fica sempre vazia, porque ele não pode alterá-la.
+func pantryOwner(r *http.Request) string {
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	return principal.Subject
+}
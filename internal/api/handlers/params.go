@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/utils"
+)
+
+// pathIDsKey é a chave do contexto onde PathParamsMiddleware guarda os IDs da rota.
+type pathIDsKey struct{}
+
+// PathParamsMiddleware converte os parâmetros de rota que são IDs ("id" e os
+// terminados em "_id") em inteiros antes de chamar o handler, respondendo 400
+// se algum deles não for um inteiro positivo. Os handlers os leem com pathID.
+func PathParamsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := make(map[string]int)
+		for name, value := range mux.Vars(r) {
+			if name != "id" && !strings.HasSuffix(name, "_id") {
+				continue
+			}
+
+			id, err := utils.StringToInt(value)
+			if err != nil || id <= 0 {
+				badRequest(w, r, fmt.Sprintf("Parâmetro %s inválido: %q não é um ID", name, value))
+				return
+			}
+			ids[name] = id
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), pathIDsKey{}, ids)))
+	})
+}
+
+// pathID retorna o ID do parâmetro de rota informado, convertido por PathParamsMiddleware.
+func pathID(r *http.Request, name string) int {
+	ids, _ := r.Context().Value(pathIDsKey{}).(map[string]int)
+	return ids[name]
+}
+
+// NotFound responde 404 com um problema às requisições que não casam com nenhuma rota.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusNotFound, codeNotFound, "Rota não encontrada", nil)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/keevferreira/recipes-api/internal/logging"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// Códigos de erro legíveis por máquina enviados no campo code dos problemas.
+const (
+	codeBadRequest       = "bad_request"
+	codeUnauthorized     = "unauthorized"
+	codeForbidden        = "forbidden"
+	codeNotFound         = "not_found"
+	codeConflict         = "conflict"
+	codeValidationFailed = "validation_failed"
+	codeRateLimited      = "rate_limited"
+	codeInternalError    = "internal_error"
+	codeUnavailable      = "unavailable"
+)
+
+// problem é o corpo de uma resposta de erro no formato RFC 7807 (application/problem+json).
+type problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Code     string              `json:"code"`
+	Errors   []models.FieldError `json:"errors,omitempty"`
+}
+
+// writeProblem envia um problema com o status, o código e o detalhe informados.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, code string, detail string, fields []models.FieldError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+		Errors:   fields,
+	})
+}
+
+// badRequest envia um problema 400 para uma requisição malformada, como um corpo
+// que não é JSON ou um parâmetro de consulta inválido.
+func badRequest(w http.ResponseWriter, r *http.Request, detail string) {
+	writeProblem(w, r, http.StatusBadRequest, codeBadRequest, detail, nil)
+}
+
+// writeError converte um erro dos modelos no problema correspondente. Erros que
+// não são de domínio são registrados no log e enviados como 500, sem expor detalhes.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var validation *models.ValidationError
+	var malformed *malformedBodyError
+	switch {
+	case errors.As(err, &malformed):
+		badRequest(w, r, malformed.Error())
+	case errors.As(err, &validation):
+		writeProblem(w, r, http.StatusUnprocessableEntity, codeValidationFailed, "A requisição contém campos inválidos", validation.Fields)
+	case errors.Is(err, models.ErrNotFound):
+		writeProblem(w, r, http.StatusNotFound, codeNotFound, err.Error(), nil)
+	case errors.Is(err, models.ErrConflict):
+		writeProblem(w, r, http.StatusConflict, codeConflict, err.Error(), nil)
+	case errors.Is(err, models.ErrForbidden):
+		writeProblem(w, r, http.StatusForbidden, codeForbidden, err.Error(), nil)
+	default:
+		logging.FromContext(r.Context()).Error("erro ao atender a requisição",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("error", err.Error()),
+		)
+		writeProblem(w, r, http.StatusInternalServerError, codeInternalError, "Erro interno do servidor", nil)
+	}
+}
+
+// InternalServerError envia um problema 500 genérico, usado pelo middleware de recuperação de panics.
+func InternalServerError(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusInternalServerError, codeInternalError, "Erro interno do servidor", nil)
+}
+
+// Unauthorized envia um problema 401 pedindo credenciais, usado pelo middleware de autenticação.
+func Unauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="recipes-api"`)
+	writeProblem(w, r, http.StatusUnauthorized, codeUnauthorized, detail, nil)
+}
+
+// TooManyRequests envia um problema 429, usado pelo middleware de limite de requisições,
+// que informa no cabeçalho Retry-After quando tentar de novo.
+func TooManyRequests(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusTooManyRequests, codeRateLimited, "Limite de requisições excedido, tente novamente mais tarde", nil)
+}
+
+// Forbidden envia um problema 403 informando a permissão que falta, usado pelo middleware de autorização.
+func Forbidden(w http.ResponseWriter, r *http.Request, permission string) {
+	writeProblem(w, r, http.StatusForbidden, codeForbidden, fmt.Sprintf("Esta rota exige a permissão %s", permission), nil)
+}
@@ -2,41 +2,65 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
-	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/auth"
 	"github.com/keevferreira/recipes-api/internal/models"
-	"github.com/keevferreira/recipes-api/internal/utils"
+	"github.com/keevferreira/recipes-api/internal/units"
+	"github.com/keevferreira/recipes-api/internal/validation"
 )
 
 // RecipeHandler é uma estrutura para manipulação de receitas.
 type RecipeHandler struct {
-	// Aqui você pode incluir dependências, como um serviço de banco de dados.
-	// Por simplicidade, vamos apenas lidar com manipulação de receitas diretamente.
+	recipes models.RecipeRepository
+	// ingredients e categories são usados para conferir as referências das receitas recebidas.
+	ingredients models.IngredientRepository
+	categories  models.CategoryRepository
+	// users é usado para conferir o autor na listagem das receitas de um usuário.
+	users models.UserRepository
 }
 
-// NewRecipeHandler cria uma nova instância de RecipeHandler.
-func NewRecipeHandler() *RecipeHandler {
-	return &RecipeHandler{}
+// NewRecipeHandler cria uma nova instância de RecipeHandler que usa os repositórios informados.
+func NewRecipeHandler(repositories *models.Repositories) *RecipeHandler {
+	return &RecipeHandler{
+		recipes:     repositories.Recipes,
+		ingredients: repositories.Ingredients,
+		categories:  repositories.Categories,
+		users:       repositories.Users,
+	}
 }
 
 // CreateRecipe cria uma nova receita.
 func (rh *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	// Decodifica o corpo da solicitação em um objeto Recipe
 	var recipe models.Recipe
-	err := json.NewDecoder(r.Body).Decode(&recipe)
+	err := decodeJSON(r, &recipe)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Valida a entrada antes de salvá-la, listando todos os campos inválidos
+	err = validation.Recipe(r.Context(), rh.ingredients, rh.categories).Validate(recipe)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	// Aqui você pode validar a entrada, como garantir que campos obrigatórios estejam presentes, etc.
+	// O autor é quem fez a requisição, nunca o informado no corpo
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	recipe.AuthorID = principal.UserID
 
 	// Salve a receita no banco de dados ou onde quer que você esteja armazenando.
 	// Suponha que haja uma função SaveRecipe no modelo de dados que manipula a persistência.
-	_, err = models.CreateRecipe(recipe)
+	_, err = rh.recipes.CreateRecipe(r.Context(), recipe)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -44,40 +68,197 @@ func (rh *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
-// GetRecipes recupera todas as receitas.
+// GetRecipes recupera uma página das receitas, com filtros e ordenação opcionais.
 func (rh *RecipeHandler) GetRecipes(w http.ResponseWriter, r *http.Request) {
+	filter, err := recipeFilterFromQuery(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+
+	rh.listRecipes(w, r, filter)
+}
+
+// GetRecipesByUserID recupera uma página das receitas escritas por um usuário, com os
+// mesmos filtros e a mesma ordenação de GetRecipes.
+func (rh *RecipeHandler) GetRecipesByUserID(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID do usuário dos parâmetros da URL
+	userID := pathID(r, "id")
+
+	filter, err := recipeFilterFromQuery(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+
+	// Um usuário inexistente responde 404, e não uma lista vazia
+	_, err = rh.users.GetUserByID(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filter.AuthorID = userID
+
+	rh.listRecipes(w, r, filter)
+}
+
+// listRecipes envia uma página das receitas que atendem ao filtro.
+func (rh *RecipeHandler) listRecipes(w http.ResponseWriter, r *http.Request, filter models.RecipeFilter) {
+	options, err := listOptionsFromQuery(r, models.RecipeSortKeys...)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	includes, err := recipeIncludesFromQuery(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+
 	// Recupere as receitas do banco de dados ou de onde quer que você esteja armazenando.
-	recipes, err := models.GetAllRecipes()
+	recipes, info, err := rh.recipes.GetAllRecipes(r.Context(), filter, includes, options)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	// Converte as unidades se o parâmetro ?units= foi informado
+	system, convert, err := unitSystemFromQuery(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	ids := make([]int, 0, len(recipes))
+	for i := range recipes {
+		if convert {
+			recipes[i] = recipes[i].ConvertedTo(system)
+		}
+		ids = append(ids, recipes[i].ID)
+	}
+	if recipes == nil {
+		recipes = []models.Recipe{}
+	}
+
 	// Serializa as receitas para JSON e envia a resposta.
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(recipes)
+	json.NewEncoder(w).Encode(newPageResponse(r, recipes, ids, options, info))
+}
+
+// SearchRecipes faz uma busca textual nas receitas e retorna os resultados mais relevantes primeiro.
+func (rh *RecipeHandler) SearchRecipes(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		badRequest(w, r, "Informe o termo da busca no parâmetro q")
+		return
+	}
+
+	options, err := listOptionsFromQuery(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	if options.Cursor != nil {
+		badRequest(w, r, "A busca não aceita o parâmetro cursor, use offset")
+		return
+	}
+
+	results, info, err := rh.recipes.SearchRecipes(r.Context(), query, options)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	ids := make([]int, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.RecipeID)
+	}
+	if results == nil {
+		results = []models.RecipeSearchResult{}
+	}
+
+	// Os resultados são ordenados por relevância, então apenas os links com offset fazem sentido
+	response := newPageResponse(r, results, ids, options, info)
+	response.NextCursor, response.PrevCursor = "", ""
+
+	// Serializa os resultados para JSON e envia a resposta.
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// recipeIncludesFromQuery lê o parâmetro include, uma lista separada por vírgulas com
+// ingredients e categories. Sem o parâmetro, as duas associações são carregadas; com ele
+// vazio, nenhuma.
+func recipeIncludesFromQuery(r *http.Request) (models.RecipeIncludes, error) {
+	values, ok := r.URL.Query()["include"]
+	if !ok {
+		return models.RecipeIncludes{Ingredients: true, Categories: true}, nil
+	}
+
+	var includes models.RecipeIncludes
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			switch strings.TrimSpace(name) {
+			case "ingredients":
+				includes.Ingredients = true
+			case "categories":
+				includes.Categories = true
+			case "":
+			default:
+				return models.RecipeIncludes{}, fmt.Errorf("parâmetro include inválido: %q, use ingredients e/ou categories", name)
+			}
+		}
+	}
+
+	return includes, nil
+}
+
+// recipeFilterFromQuery lê os filtros difficulty, max_prep_time, category_id e ingredient_id da query string.
+func recipeFilterFromQuery(r *http.Request) (models.RecipeFilter, error) {
+	filter := models.RecipeFilter{Difficulty: r.URL.Query().Get("difficulty")}
+	if filter.Difficulty != "" && !slices.Contains(models.Difficulties, filter.Difficulty) {
+		return models.RecipeFilter{}, fmt.Errorf("parâmetro difficulty inválido, use %s", strings.Join(models.Difficulties, ", "))
+	}
+
+	fields := []struct {
+		name  string
+		value *int
+	}{
+		{"max_prep_time", &filter.MaxPrepTime},
+		{"category_id", &filter.CategoryID},
+		{"ingredient_id", &filter.IngredientID},
+	}
+	for _, field := range fields {
+		value, err := intFromQuery(r, field.name, 0)
+		if err != nil || value < 0 {
+			return models.RecipeFilter{}, fmt.Errorf("parâmetro %s inválido", field.name)
+		}
+		*field.value = value
+	}
+
+	return filter, nil
 }
 
 func (rh *RecipeHandler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID da receita dos parâmetros da URL
-	recipeID := mux.Vars(r)["id"]
-
-	// Converte o ID da receita de string para inteiro
-	id := utils.StringToInt(recipeID)
+	recipeID := pathID(r, "id")
 
 	// Aqui, estamos simulando a busca de uma receita em um banco de dados.
-	recipe, err := models.GetRecipeByID(id)
+	recipe, err := rh.recipes.GetRecipeByID(r.Context(), recipeID)
 	if err != nil {
-		// Se ocorrer um erro ao buscar a receita, retorna um erro interno do servidor
-		http.Error(w, "Erro ao buscar a receita", http.StatusInternalServerError)
+		// Se ocorrer um erro ao buscar a receita, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
-	// Se a receita não for encontrada, retorna um erro de não encontrado
-	if recipe.ID == 0 {
-		http.Error(w, "Receita não encontrada", http.StatusNotFound)
+	// Converte as unidades se o parâmetro ?units= foi informado
+	system, convert, err := unitSystemFromQuery(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
 		return
 	}
+	if convert {
+		recipe = recipe.ConvertedTo(system)
+	}
 
 	// Se a receita for encontrada, retorne-a como resposta em formato JSON
 	w.Header().Set("Content-Type", "application/json")
@@ -86,23 +267,39 @@ func (rh *RecipeHandler) GetRecipeByID(w http.ResponseWriter, r *http.Request) {
 
 func (rh *RecipeHandler) UpdateRecipeByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID da receita dos parâmetros da URL
-	recipeID := mux.Vars(r)["id"]
+	recipeID := pathID(r, "id")
+
+	// Só o autor da receita ou quem tem a permissão recipes:manage podem alterá-la
+	recipe, err := rh.authorizeRecipeChange(r, recipeID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
 	// Decodifica o corpo da solicitação em um objeto Recipe
 	var updatedRecipe models.Recipe
-	err := json.NewDecoder(r.Body).Decode(&updatedRecipe)
+	err = decodeJSON(r, &updatedRecipe)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Valida a entrada antes de salvá-la, listando todos os campos inválidos
+	err = validation.Recipe(r.Context(), rh.ingredients, rh.categories).Validate(updatedRecipe)
 	if err != nil {
-		http.Error(w, "Erro ao decodificar o corpo da solicitação", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
+	// O autor não muda com a atualização
+	updatedRecipe.AuthorID = recipe.AuthorID
 
 	// Supondo que você tenha uma função que atualize a receita com base no ID
 	// Aqui, estamos simulando a atualização de uma receita em um banco de dados.
 	// Você precisaria implementar essa função de acordo com sua lógica de negócios e banco de dados.
-	err = models.UpdateRecipeByID(utils.StringToInt(recipeID), updatedRecipe)
+	err = rh.recipes.UpdateRecipeByID(r.Context(), recipeID, updatedRecipe)
 	if err != nil {
-		// Se ocorrer um erro ao atualizar a receita, retorna um erro interno do servidor
-		http.Error(w, "Erro ao atualizar a receita", http.StatusInternalServerError)
+		// Se ocorrer um erro ao atualizar a receita, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
@@ -113,18 +310,186 @@ func (rh *RecipeHandler) UpdateRecipeByID(w http.ResponseWriter, r *http.Request
 
 func (rh *RecipeHandler) DeleteRecipeByID(w http.ResponseWriter, r *http.Request) {
 	// Extrai o ID da receita dos parâmetros da URL
-	recipeID := mux.Vars(r)["id"]
+	recipeID := pathID(r, "id")
+
+	// Só o autor da receita ou quem tem a permissão recipes:manage podem excluí-la
+	_, err := rh.authorizeRecipeChange(r, recipeID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
 	// Supondo que você tenha uma função que delete a receita com base no ID
 	// Aqui, estamos simulando a exclusão de uma receita em um banco de dados.
 	// Você precisaria implementar essa função de acordo com sua lógica de negócios e banco de dados.
-	err := models.DeleteRecipeByID(utils.StringToInt(recipeID))
+	err = rh.recipes.DeleteRecipeByID(r.Context(), recipeID)
 	if err != nil {
-		// Se ocorrer um erro ao deletar a receita, retorna um erro interno do servidor
-		http.Error(w, "Erro ao deletar a receita", http.StatusInternalServerError)
+		// Se ocorrer um erro ao deletar a receita, retorna o problema correspondente
+		writeError(w, r, err)
 		return
 	}
 
 	// Se a receita foi deletada com sucesso, retorne um status OK
 	w.WriteHeader(http.StatusOK)
 }
+
+// stepOrderRequest é o corpo da requisição de reordenação dos passos de uma receita.
+type stepOrderRequest struct {
+	StepIDs []int `json:"step_ids"`
+}
+
+// ReorderRecipeSteps reordena os passos de preparo de uma receita.
+func (rh *RecipeHandler) ReorderRecipeSteps(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID da receita dos parâmetros da URL
+	recipeID := pathID(r, "id")
+
+	// Só o autor da receita ou quem tem a permissão recipes:manage podem reordenar os passos
+	_, err := rh.authorizeRecipeChange(r, recipeID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Decodifica o corpo da solicitação com a nova ordem dos passos
+	var order stepOrderRequest
+	err = decodeJSON(r, &order)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = rh.recipes.ReorderStepsByRecipeID(r.Context(), recipeID, order.StepIDs)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	steps, err := rh.recipes.GetStepsByRecipeID(r.Context(), recipeID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna os passos na nova ordem como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(steps)
+}
+
+// authorizeRecipeChange retorna a receita com o ID informado se quem fez a requisição
+// puder alterá-la: o autor dela ou quem tem a permissão recipes:manage. Os demais
+// recebem um erro de acesso negado, inclusive para as receitas sem autor.
+func (rh *RecipeHandler) authorizeRecipeChange(r *http.Request, recipeID int) (models.Recipe, error) {
+	recipe, err := rh.recipes.GetRecipeByID(r.Context(), recipeID)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	if principal.Can(auth.PermRecipesManage) || (principal.UserID != 0 && principal.UserID == recipe.AuthorID) {
+		return recipe, nil
+	}
+
+	return models.Recipe{}, models.NewForbiddenError("only the author of recipe %d may change it without the %s permission", recipeID, auth.PermRecipesManage)
+}
+
+// scaledRecipeResponse é a receita escalada junto com o fator aplicado.
+type scaledRecipeResponse struct {
+	models.Recipe
+	ScaleFactor float64 `json:"scale_factor"`
+}
+
+// GetScaledRecipe retorna uma receita com as quantidades escaladas para um
+// número de porções (?servings=N) ou para a quantidade disponível de um
+// ingrediente (?ingredient_id=X&amount=Y). A quantidade está na unidade usada
+// pela receita ou na informada em &unit=Z, convertida com a densidade do
+// ingrediente quando necessário. Aceita também ?units=metric|us.
+func (rh *RecipeHandler) GetScaledRecipe(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID da receita dos parâmetros da URL
+	id := pathID(r, "id")
+
+	recipe, err := rh.recipes.GetRecipeByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Calcula o fator de escala a partir dos parâmetros da consulta
+	query := r.URL.Query()
+	var factor float64
+	switch {
+	case query.Get("servings") != "":
+		servings, convErr := strconv.Atoi(query.Get("servings"))
+		if convErr != nil {
+			badRequest(w, r, "Parâmetro servings inválido")
+			return
+		}
+		factor, err = recipe.ScaleFactorForServings(servings)
+
+	case query.Get("ingredient_id") != "" && query.Get("amount") != "":
+		ingredientID, convErr := strconv.Atoi(query.Get("ingredient_id"))
+		if convErr != nil {
+			badRequest(w, r, "Parâmetro ingredient_id inválido")
+			return
+		}
+		amount, convErr := strconv.ParseFloat(query.Get("amount"), 64)
+		if convErr != nil {
+			badRequest(w, r, "Parâmetro amount inválido")
+			return
+		}
+
+		// A densidade do ingrediente só é necessária quando há uma unidade a converter
+		unit := query.Get("unit")
+		var density float64
+		if unit != "" {
+			ingredient, err := rh.ingredients.GetIngredientByID(r.Context(), ingredientID)
+			if err != nil && !errors.Is(err, models.ErrNotFound) {
+				writeError(w, r, err)
+				return
+			}
+			if ingredient.Density != nil {
+				density = *ingredient.Density
+			}
+		}
+		factor, err = recipe.ScaleFactorForIngredient(ingredientID, amount, unit, density)
+
+	default:
+		badRequest(w, r, "Informe servings ou ingredient_id e amount")
+		return
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	scaled := recipe.Scaled(factor)
+
+	// Converte as unidades se o parâmetro ?units= foi informado
+	system, convert, err := unitSystemFromQuery(r)
+	if err != nil {
+		badRequest(w, r, err.Error())
+		return
+	}
+	if convert {
+		scaled = scaled.ConvertedTo(system)
+	}
+
+	// Retorna a receita escalada como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scaledRecipeResponse{Recipe: scaled, ScaleFactor: factor})
+}
+
+// unitSystemFromQuery lê o sistema de medidas do parâmetro ?units=metric|us.
+// convert é falso quando o parâmetro não foi informado.
+func unitSystemFromQuery(r *http.Request) (system units.System, convert bool, err error) {
+	name := r.URL.Query().Get("units")
+	if name == "" {
+		return "", false, nil
+	}
+
+	system, err = units.ParseSystem(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	return system, true, nil
+}
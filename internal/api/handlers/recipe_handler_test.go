@@ -0,0 +1,208 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/database/memory"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+var (
+	alice  = auth.Principal{Subject: "user:1", UserID: 1, Role: models.RoleAuthor}
+	bob    = auth.Principal{Subject: "user:2", UserID: 2, Role: models.RoleAuthor}
+	editor = auth.Principal{Subject: "user:3", UserID: 3, Role: models.RoleEditor}
+)
+
+// recipeServer serves the recipe handler over the in-memory repositories.
+type recipeServer struct {
+	router       *mux.Router
+	repositories *models.Repositories
+}
+
+func newRecipeServer() *recipeServer {
+	repositories := memory.NewRepositories(memory.NewStore())
+	recipeHandler := handlers.NewRecipeHandler(repositories)
+
+	router := mux.NewRouter()
+	router.Use(handlers.PathParamsMiddleware)
+	router.HandleFunc("/recipes/", recipeHandler.CreateRecipe).Methods("POST")
+	router.HandleFunc("/recipe/{id:[0-9]+}", recipeHandler.GetRecipeByID).Methods("GET")
+	router.HandleFunc("/recipe/{id:[0-9]+}", recipeHandler.UpdateRecipeByID).Methods("PUT")
+	router.HandleFunc("/recipe/{id:[0-9]+}", recipeHandler.DeleteRecipeByID).Methods("DELETE")
+
+	return &recipeServer{router: router, repositories: repositories}
+}
+
+// do sends a request as principal, as the authentication middleware would.
+func (s *recipeServer) do(principal auth.Principal, method string, target string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+// problemFields returns the code of a problem and its invalid fields as
+// "field:code".
+func problemFields(t *testing.T, w *httptest.ResponseRecorder) (string, []string) {
+	t.Helper()
+
+	var problem struct {
+		Code   string              `json:"code"`
+		Errors []models.FieldError `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decoding the problem: %v", err)
+	}
+
+	var fields []string
+	for _, field := range problem.Errors {
+		fields = append(fields, field.Field+":"+field.Code)
+	}
+	return problem.Code, fields
+}
+
+func TestCreateRecipe(t *testing.T) {
+	s := newRecipeServer()
+	flour, _ := s.repositories.Ingredients.CreateIngredient(context.Background(), models.Ingredient{Name: "Farinha"})
+
+	// The author comes from the principal, not from the body
+	body := `{"title": "Pão", "difficulty": "easy", "author_id": 9,
+		"ingredients": [{"ingredient_id": ` + strconv.Itoa(flour) + `, "quantity": 500, "unit": "g"}],
+		"steps": [{"text": "Sove a massa.", "ingredient_ids": [` + strconv.Itoa(flour) + `]}]}`
+	w := s.do(alice, "POST", "/recipes/", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+	}
+
+	recipes, _, err := s.repositories.Recipes.GetAllRecipes(context.Background(), models.RecipeFilter{}, models.RecipeIncludes{}, models.ListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAllRecipes: %v", err)
+	}
+	if len(recipes) != 1 || recipes[0].Title != "Pão" || recipes[0].AuthorID != alice.UserID {
+		t.Errorf("recipes = %+v, want the new recipe written by user 1", recipes)
+	}
+}
+
+func TestCreateRecipeInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+		fields []string
+	}{
+		{"malformed", `{"title": `, http.StatusBadRequest, "bad_request", nil},
+		{"unknown field", `{"title": "Pão", "difficulty": "easy", "chef": "Ana"}`, http.StatusUnprocessableEntity, "validation_failed", []string{"chef:unknown"}},
+		{"invalid fields", `{"title": " ", "difficulty": "trivial"}`, http.StatusUnprocessableEntity, "validation_failed", []string{
+			"title:required", "difficulty:invalid",
+		}},
+		{"missing ingredient", `{"title": "Pão", "difficulty": "easy", "ingredients": [{"ingredient_id": 99, "quantity": 1, "unit": "g"}]}`,
+			http.StatusUnprocessableEntity, "validation_failed", []string{"ingredients[0].ingredient_id:not_found"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newRecipeServer()
+
+			w := s.do(alice, "POST", "/recipes/", test.body)
+			if w.Code != test.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, test.status, w.Body)
+			}
+
+			code, fields := problemFields(t, w)
+			if code != test.code || !slices.Equal(fields, test.fields) {
+				t.Errorf("problem = %s %v, want %s %v", code, fields, test.code, test.fields)
+			}
+
+			recipes, _, _ := s.repositories.Recipes.GetAllRecipes(context.Background(), models.RecipeFilter{}, models.RecipeIncludes{}, models.ListOptions{Limit: 10})
+			if len(recipes) != 0 {
+				t.Errorf("recipes = %+v, want none saved", recipes)
+			}
+		})
+	}
+}
+
+func TestGetRecipeByID(t *testing.T) {
+	s := newRecipeServer()
+	id, _ := s.repositories.Recipes.CreateRecipe(context.Background(), models.Recipe{Title: "Pão", Difficulty: models.DifficultyEasy})
+
+	w := s.do(auth.Anonymous, "GET", "/recipe/"+strconv.Itoa(id), "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	var recipe models.Recipe
+	if err := json.NewDecoder(w.Body).Decode(&recipe); err != nil || recipe.ID != id || recipe.Title != "Pão" {
+		t.Errorf("recipe = %+v (%v), want the recipe %d", recipe, err, id)
+	}
+
+	w = s.do(auth.Anonymous, "GET", "/recipe/99", "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status of a missing recipe = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if code, _ := problemFields(t, w); code != "not_found" {
+		t.Errorf("code of a missing recipe = %s, want not_found", code)
+	}
+}
+
+func TestChangeRecipeOwnership(t *testing.T) {
+	const body = `{"title": "Pão caseiro", "difficulty": "easy"}`
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		method    string
+		status    int
+	}{
+		{"update by another author", bob, "PUT", http.StatusForbidden},
+		{"delete by another author", bob, "DELETE", http.StatusForbidden},
+		{"update by the author", alice, "PUT", http.StatusOK},
+		{"delete by the author", alice, "DELETE", http.StatusOK},
+		{"update by an editor", editor, "PUT", http.StatusOK},
+		{"delete by an editor", editor, "DELETE", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newRecipeServer()
+			id, _ := s.repositories.Recipes.CreateRecipe(context.Background(), models.Recipe{
+				Title:      "Pão",
+				Difficulty: models.DifficultyEasy,
+				AuthorID:   alice.UserID,
+			})
+
+			w := s.do(test.principal, test.method, "/recipe/"+strconv.Itoa(id), body)
+			if w.Code != test.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, test.status, w.Body)
+			}
+
+			if test.status != http.StatusForbidden {
+				return
+			}
+			if code, _ := problemFields(t, w); code != "forbidden" {
+				t.Errorf("code = %s, want forbidden", code)
+			}
+			recipe, err := s.repositories.Recipes.GetRecipeByID(context.Background(), id)
+			if err != nil || recipe.Title != "Pão" {
+				t.Errorf("GetRecipeByID after a refused change = %+v, %v, want the recipe unchanged", recipe, err)
+			}
+		})
+	}
+
+	s := newRecipeServer()
+	w := s.do(alice, "PUT", "/recipe/99", body)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status of the update of a missing recipe = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// ShoppingListHandler é uma estrutura para manipulação de listas de compras.
+type ShoppingListHandler struct {
+	recipes       models.RecipeRepository
+	ingredients   models.IngredientRepository
+	shoppingLists models.ShoppingListRepository
+}
+
+// NewShoppingListHandler cria uma nova instância de ShoppingListHandler que usa os repositórios informados.
+func NewShoppingListHandler(repositories *models.Repositories) *ShoppingListHandler {
+	return &ShoppingListHandler{
+		recipes:       repositories.Recipes,
+		ingredients:   repositories.Ingredients,
+		shoppingLists: repositories.ShoppingLists,
+	}
+}
+
+// shoppingListRequest é o corpo da requisição de criação de uma lista de compras.
+type shoppingListRequest struct {
+	Name    string `json:"name"`
+	Recipes []struct {
+		RecipeID int `json:"recipe_id"`
+		// Servings é o número de porções desejado, ou zero para usar o rendimento da receita.
+		Servings int `json:"servings"`
+	} `json:"recipes"`
+}
+
+// shoppingListResponse é uma lista de compras com os itens agrupados por corredor.
+type shoppingListResponse struct {
+	ID        int                        `json:"id"`
+	Name      string                     `json:"name"`
+	Owner     string                     `json:"owner"`
+	Aisles    []models.ShoppingListAisle `json:"aisles"`
+	CreatedAt time.Time                  `json:"created_at"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+}
+
+// CreateShoppingList gera e salva uma lista de compras a partir de um conjunto de receitas.
+func (sh *ShoppingListHandler) CreateShoppingList(w http.ResponseWriter, r *http.Request) {
+	// Decodifica o corpo da solicitação em um objeto shoppingListRequest
+	var request shoppingListRequest
+	err := decodeJSON(r, &request)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if len(request.Recipes) == 0 {
+		badRequest(w, r, "Informe ao menos uma receita")
+		return
+	}
+
+	// Junta as linhas de ingredientes de todas as receitas, já escaladas.
+	// Uma receita inexistente interrompe a criação com o erro correspondente.
+	var lines []models.RecipeIngredient
+	var ingredientIDs []int
+	seen := make(map[int]bool)
+	for _, requested := range request.Recipes {
+		recipe, err := sh.recipes.GetRecipeByID(r.Context(), requested.RecipeID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		factor := 1.0
+		if requested.Servings != 0 {
+			factor, err = recipe.ScaleFactorForServings(requested.Servings)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+		}
+
+		for _, line := range recipe.Ingredients {
+			line.Quantity *= factor
+			lines = append(lines, line)
+
+			if !seen[line.IngredientID] {
+				seen[line.IngredientID] = true
+				ingredientIDs = append(ingredientIDs, line.IngredientID)
+			}
+		}
+	}
+
+	// Busca a densidade e o corredor de todos os ingredientes de uma só vez
+	ingredients, err := sh.ingredients.GetIngredientsByIDs(r.Context(), ingredientIDs)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	catalog := make(map[int]models.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		catalog[ingredient.ID] = ingredient
+	}
+
+	// A lista pertence a quem a criou
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	list := models.ShoppingList{
+		Name:  request.Name,
+		Owner: principal.Subject,
+		Items: models.MergeShoppingItems(lines, catalog),
+	}
+	if list.Name == "" {
+		list.Name = "Lista de compras"
+	}
+
+	id, err := sh.shoppingLists.CreateShoppingList(r.Context(), list)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err = sh.shoppingLists.GetShoppingListByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna a lista criada como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newShoppingListResponse(list))
+}
+
+// GetShoppingListByID recupera uma lista de compras.
+func (sh *ShoppingListHandler) GetShoppingListByID(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID da lista dos parâmetros da URL
+	id := pathID(r, "id")
+
+	list, err := sh.authorizeShoppingList(r, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna a lista como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newShoppingListResponse(list))
+}
+
+// DeleteShoppingListByID exclui uma lista de compras.
+func (sh *ShoppingListHandler) DeleteShoppingListByID(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID da lista dos parâmetros da URL
+	id := pathID(r, "id")
+
+	_, err := sh.authorizeShoppingList(r, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = sh.shoppingLists.DeleteShoppingListByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Se a lista foi deletada com sucesso, retorne um status OK
+	w.WriteHeader(http.StatusOK)
+}
+
+// checkItemRequest é o corpo da requisição que marca um item como comprado.
+type checkItemRequest struct {
+	Checked bool `json:"checked"`
+}
+
+// CheckShoppingListItem marca ou desmarca um item de uma lista de compras.
+func (sh *ShoppingListHandler) CheckShoppingListItem(w http.ResponseWriter, r *http.Request) {
+	// Extrai os IDs da lista e do item dos parâmetros da URL
+	listID := pathID(r, "id")
+	itemID := pathID(r, "item_id")
+
+	// Decodifica o corpo da solicitação em um objeto checkItemRequest
+	var request checkItemRequest
+	err := decodeJSON(r, &request)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	_, err = sh.authorizeShoppingList(r, listID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = sh.shoppingLists.CheckShoppingListItem(r.Context(), listID, itemID, request.Checked)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := sh.shoppingLists.GetShoppingListByID(r.Context(), listID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna a lista atualizada como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newShoppingListResponse(list))
+}
+
+// authorizeShoppingList retorna a lista de compras com o ID informado se quem fez a
+// requisição puder acessá-la: o dono dela ou quem tem a permissão planning:manage. Os
+// demais recebem um erro de acesso negado, inclusive para as listas sem dono.
+func (sh *ShoppingListHandler) authorizeShoppingList(r *http.Request, listID int) (models.ShoppingList, error) {
+	list, err := sh.shoppingLists.GetShoppingListByID(r.Context(), listID)
+	if err != nil {
+		return models.ShoppingList{}, err
+	}
+
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	if principal.Can(auth.PermPlanningManage) || (list.Owner != "" && list.Owner == principal.Subject) {
+		return list, nil
+	}
+
+	return models.ShoppingList{}, models.NewForbiddenError("only the owner of shopping list %d may access it without the %s permission", listID, auth.PermPlanningManage)
+}
+
+// newShoppingListResponse agrupa os itens da lista por corredor.
+func newShoppingListResponse(list models.ShoppingList) shoppingListResponse {
+	return shoppingListResponse{
+		ID:        list.ID,
+		Name:      list.Name,
+		Owner:     list.Owner,
+		Aisles:    list.GroupByAisle(),
+		CreatedAt: list.CreatedAt,
+		UpdatedAt: list.UpdatedAt,
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
+	"github.com/keevferreira/recipes-api/internal/validation"
+)
+
+// UserHandler é uma estrutura para o cadastro, o login e a administração dos usuários.
+type UserHandler struct {
+	users models.UserRepository
+	// authenticator emite os tokens dos usuários que fazem login.
+	authenticator *auth.Authenticator
+}
+
+// NewUserHandler cria uma nova instância de UserHandler que usa o repositório e o autenticador informados.
+func NewUserHandler(users models.UserRepository, authenticator *auth.Authenticator) *UserHandler {
+	return &UserHandler{users: users, authenticator: authenticator}
+}
+
+// Register cadastra um novo usuário com o papel de autor.
+func (uh *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
+	// Decodifica o corpo da solicitação em um objeto Registration
+	var registration models.Registration
+	err := decodeJSON(r, &registration)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// O e-mail é guardado em minúsculas para que o login não dependa da caixa
+	registration.Email = strings.ToLower(strings.TrimSpace(registration.Email))
+
+	// Valida a entrada antes de salvá-la, listando todos os campos inválidos
+	err = validation.Registration.Validate(registration)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hash, err := auth.HashPassword(registration.Password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user := models.User{
+		Email:        registration.Email,
+		Name:         registration.Name,
+		PasswordHash: hash,
+		Role:         models.RoleAuthor,
+	}
+	user.ID, err = uh.users.CreateUser(r.Context(), user)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Lê o usuário de volta para responder com as datas gravadas
+	user, err = uh.users.GetUserByID(r.Context(), user.ID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna o usuário criado como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+// loginRequest é o corpo da requisição de login.
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// loginResponse é o token emitido no login, a ser enviado no cabeçalho Authorization.
+type loginResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Login confere o e-mail e a senha de um usuário e emite um token para ele.
+func (uh *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
+	// Decodifica o corpo da solicitação com as credenciais
+	var credentials loginRequest
+	err := decodeJSON(r, &credentials)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := uh.users.GetUserByEmail(r.Context(), strings.ToLower(strings.TrimSpace(credentials.Email)))
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		writeError(w, r, err)
+		return
+	}
+
+	// Um e-mail desconhecido também passa pela comparação da senha, para que o tempo da resposta
+	// não revele quais e-mails têm conta
+	valid, err := auth.CheckPassword(user.PasswordHash, credentials.Password)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !valid {
+		Unauthorized(w, r, "E-mail ou senha inválidos")
+		return
+	}
+
+	token, expiresAt, err := uh.authenticator.IssueToken(user)
+	if errors.Is(err, auth.ErrTokenSigningDisabled) {
+		writeProblem(w, r, http.StatusServiceUnavailable, codeUnavailable, "O login está desabilitado neste servidor", nil)
+		return
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna o token como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loginResponse{Token: token, TokenType: "Bearer", ExpiresAt: expiresAt})
+}
+
+// GetUserByID recupera um usuário, com o papel dele.
+func (uh *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID do usuário dos parâmetros da URL
+	userID := pathID(r, "id")
+
+	user, err := uh.users.GetUserByID(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna o usuário como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// SetUserRole altera o papel de um usuário. A mudança vale a partir da próxima
+// requisição dele, pois o papel é lido a cada autenticação.
+func (uh *UserHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	// Extrai o ID do usuário dos parâmetros da URL
+	userID := pathID(r, "id")
+
+	// Decodifica o corpo da solicitação com o novo papel
+	var assignment models.RoleAssignment
+	err := decodeJSON(r, &assignment)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Valida a entrada antes de salvá-la, listando todos os campos inválidos
+	err = validation.RoleAssignment.Validate(assignment)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = uh.users.SetUserRole(r.Context(), userID, assignment.Role)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := uh.users.GetUserByID(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Retorna o usuário atualizado como resposta em formato JSON
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
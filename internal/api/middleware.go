@@ -1,17 +1,416 @@
 package api
 
 import (
-	"log"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/logging"
+	"github.com/keevferreira/recipes-api/internal/metrics"
+	"github.com/keevferreira/recipes-api/internal/ratelimit"
+	"github.com/keevferreira/recipes-api/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// MiddlewareLogging é um middleware para registrar informações sobre as requisições HTTP.
+// RequestIDHeader é o cabeçalho que carrega o ID da requisição.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limita os IDs aceitos dos clientes, para que não poluam os logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware aceita o X-Request-ID enviado pelo cliente, ou gera um novo, e o
+// coloca no contexto da requisição e no cabeçalho da resposta.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// newRequestID gera um ID aleatório de 128 bits em hexadecimal.
+func newRequestID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// statusRecorder guarda o status e o número de bytes escritos na resposta.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if sr.status == 0 {
+		sr.status = status
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	n, err := sr.ResponseWriter.Write(b)
+	sr.bytes += n
+	return n, err
+}
+
+// Unwrap permite ao http.ResponseController alcançar o ResponseWriter original.
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// TracingMiddleware abre um span por requisição, nomeado pelo método e pelo modelo da rota,
+// continuando o trace recebido no cabeçalho traceparent (W3C Trace Context) quando houver.
+func TracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		route := routeTemplate(r)
+		name := r.Method
+		if route != "" {
+			name += " " + route
+		}
+		ctx, span := tracing.Tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(r.URL.Path),
+				semconv.UserAgentOriginal(r.UserAgent()),
+				semconv.ClientAddress(r.RemoteAddr),
+			),
+		)
+		defer span.End()
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		// Chamada para o próximo handler
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// LoggingMiddleware registra um log de acesso por requisição, com o status, os bytes
+// enviados, a latência e o modelo da rota, como /recipe/{id:[0-9]+}.
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Logging
-		log.Printf("Request: %s %s", r.Method, r.RequestURI)
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		// Chamada para o próximo handler
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		logging.FromContext(r.Context()).LogAttrs(r.Context(), level, "requisição atendida",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", routeTemplate(r)),
+			slog.Int("status", recorder.status),
+			slog.Int("bytes", recorder.bytes),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.String("user_agent", r.UserAgent()),
+		)
+	})
+}
+
+// unmatchedRoute é o rótulo das requisições que não casaram com nenhuma rota, para que
+// caminhos arbitrários não criem uma série nova cada um.
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware conta as requisições e observa a latência delas, rotuladas pelo método,
+// pelo modelo da rota e pelo status da resposta.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		// Chamada para o próximo handler
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		route := routeTemplate(r)
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		labels := []string{r.Method, route, strconv.Itoa(recorder.status)}
+		metrics.RequestsTotal.WithLabelValues(labels...).Inc()
+		metrics.RequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+	})
+}
+
+// routeTemplate retorna o modelo da rota que atendeu a requisição, ou "" se nenhuma casou.
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	return template
+}
+
+// RecoveryMiddleware recupera os panics dos handlers, registrando-os no log com a pilha
+// de chamadas e respondendo 500, para que uma requisição com problema não derrube o serviço.
+func RecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// http.ErrAbortHandler é a forma prevista de abortar uma resposta e não é um erro
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			logging.FromContext(r.Context()).Error("panic ao atender a requisição",
+				slog.Any("panic", recovered),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("stack", string(debug.Stack())),
+			)
+			handlers.InternalServerError(w, r)
+		}()
 
 		// Chamada para o próximo handler
 		next.ServeHTTP(w, r)
 	})
 }
+
+// AuthMiddleware autentica a requisição pelo cabeçalho X-API-Key ou por um token Bearer e
+// coloca o principal no contexto. Credenciais inválidas são recusadas com 401; sem
+// credenciais, só as leituras passam, como auth.Anonymous, e apenas se anonymousReads
+// for verdadeiro. Cada recusa gasta um token do balde do IP em store, limitado por
+// failures, e o IP que esgota o balde recebe 429 sem ser autenticado até ele voltar a
+// encher, para que chaves e tokens não possam ser adivinhados por tentativa e erro.
+func AuthMiddleware(authenticator *auth.Authenticator, anonymousReads bool, store ratelimit.Store, failures ratelimit.Limit) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := clientIP(r) + "|" + ratelimit.ClassAuthFailures
+			if !failures.Unlimited() {
+				result, err := store.Peek(r.Context(), key, failures)
+				if err != nil {
+					logging.FromContext(r.Context()).Error("erro ao conferir o limite de requisições", slog.String("error", err.Error()))
+				} else if !result.Allowed {
+					w.Header().Set("Retry-After", seconds(result.RetryAfter))
+					metrics.RateLimited.WithLabelValues(ratelimit.ClassAuthFailures).Inc()
+					handlers.TooManyRequests(w, r)
+					return
+				}
+			}
+
+			principal, err := authenticator.Authenticate(r)
+			switch {
+			case errors.Is(err, auth.ErrNoCredentials):
+				if !anonymousReads || !isRead(r) {
+					handlers.Unauthorized(w, r, "Esta rota exige autenticação")
+					return
+				}
+				r = r.WithContext(auth.WithPrincipal(r.Context(), auth.Anonymous))
+			case errors.Is(err, auth.ErrInvalidCredentials):
+				logging.FromContext(r.Context()).Info("credenciais recusadas", slog.String("error", err.Error()))
+				if !failures.Unlimited() {
+					_, err = store.Take(r.Context(), key, failures)
+					if err != nil {
+						logging.FromContext(r.Context()).Error("erro ao conferir o limite de requisições", slog.String("error", err.Error()))
+					}
+				}
+				handlers.Unauthorized(w, r, "Credenciais inválidas")
+				return
+			case err != nil:
+				logging.FromContext(r.Context()).Error("erro ao autenticar a requisição", slog.String("error", err.Error()))
+				handlers.InternalServerError(w, r)
+				return
+			default:
+				r = r.WithContext(auth.WithPrincipal(r.Context(), principal))
+			}
+
+			// Chamada para o próximo handler
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isRead indica se a requisição só lê dados.
+func isRead(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+}
+
+// RequirePermission recusa com 403 as requisições cujo principal, colocado no contexto por
+// AuthMiddleware, não tem a permissão informada no papel dele. Cada rota da API é
+// registrada com a permissão que exige.
+func RequirePermission(permission auth.Permission) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ := auth.PrincipalFromContext(r.Context())
+			if !principal.Can(permission) {
+				logging.FromContext(r.Context()).Info("permissão negada",
+					slog.String("subject", principal.Subject),
+					slog.String("role", principal.Role),
+					slog.String("permission", string(permission)),
+				)
+				handlers.Forbidden(w, r, string(permission))
+				return
+			}
+
+			// Chamada para o próximo handler
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimitMiddleware limita as requisições de cada cliente com os baldes de tokens guardados
+// em store. O cliente é o principal colocado no contexto por AuthMiddleware, ou o IP de quem
+// fez a requisição quando não há um, e cada cliente tem um balde por classe de requisição
+// (leitura, escrita e busca) e por rota com limite próprio. As respostas levam os cabeçalhos
+// RateLimit-*, e as requisições acima do limite recebem 429 com Retry-After.
+func RateLimitMiddleware(store ratelimit.Store, limits ratelimit.Limits) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name, limit := rateLimitFor(limits, r)
+			if limit.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := store.Take(r.Context(), rateLimitClient(r)+"|"+name, limit)
+			if err != nil {
+				// Uma falha no armazenamento dos baldes não deve derrubar a API, então a requisição passa
+				logging.FromContext(r.Context()).Error("erro ao conferir o limite de requisições", slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", seconds(result.Reset))
+			header.Set("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+seconds(limit.Period))
+			if !result.Allowed {
+				header.Set("Retry-After", seconds(result.RetryAfter))
+				metrics.RateLimited.WithLabelValues(name).Inc()
+				handlers.TooManyRequests(w, r)
+				return
+			}
+
+			// Chamada para o próximo handler
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IPRateLimitMiddleware limita as requisições de cada IP com os baldes guardados em store,
+// antes da autenticação: assim quem envia credenciais não consegue fazer o banco consultá-las
+// mais rápido do que limit permite. Os limites por cliente de RateLimitMiddleware, que vem
+// depois de AuthMiddleware, continuam valendo; as requisições acima deste recebem 429.
+func IPRateLimitMiddleware(store ratelimit.Store, limit ratelimit.Limit) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := store.Take(r.Context(), clientIP(r)+"|"+ratelimit.ClassIP, limit)
+			if err != nil {
+				// Uma falha no armazenamento dos baldes não deve derrubar a API, então a requisição passa
+				logging.FromContext(r.Context()).Error("erro ao conferir o limite de requisições", slog.String("error", err.Error()))
+			} else if !result.Allowed {
+				w.Header().Set("Retry-After", seconds(result.RetryAfter))
+				metrics.RateLimited.WithLabelValues(ratelimit.ClassIP).Inc()
+				handlers.TooManyRequests(w, r)
+				return
+			}
+
+			// Chamada para o próximo handler
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitFor retorna o nome e o limite do balde da requisição: o da rota, se ela tiver
+// um limite próprio, ou o da classe dela. As rotas terminadas em /search são buscas.
+func rateLimitFor(limits ratelimit.Limits, r *http.Request) (string, ratelimit.Limit) {
+	route := routeTemplate(r)
+	key := ratelimit.RouteKey(r.Method, route)
+	if limit, ok := limits.Routes[key]; ok {
+		return key, limit
+	}
+
+	switch {
+	case strings.HasSuffix(route, "/search"):
+		return ratelimit.ClassSearch, limits.Search
+	case isRead(r):
+		return ratelimit.ClassRead, limits.Read
+	default:
+		return ratelimit.ClassWrite, limits.Write
+	}
+}
+
+// rateLimitClient identifica o cliente da requisição: o principal autenticado ou, sem
+// credenciais, o IP de origem. Atrás de um proxy, todos os clientes anônimos dividem o IP dele.
+func rateLimitClient(r *http.Request) string {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if ok && principal.Method != "" {
+		return principal.Method + ":" + principal.Subject
+	}
+
+	return clientIP(r)
+}
+
+// clientIP identifica o cliente da requisição pelo IP de origem.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds formata uma duração em segundos inteiros, arredondando para cima.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
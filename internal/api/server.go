@@ -1,17 +1,59 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/config"
 )
 
-func InitializeServer(port string, routerControler *mux.Router) {
-	fmt.Printf("Servidor escutando em %s", port)
-	err := http.ListenAndServe(":"+port, routerControler)
-	if err != nil {
-		log.Fatalf("Erro ao iniciar o servidor: %v", err)
+// InitializeServer inicia o servidor HTTP e o mantém até receber SIGINT ou SIGTERM.
+// Ao receber o sinal, para de aceitar conexões e aguarda as requisições em andamento
+// até o prazo de encerramento. Retorna quando o servidor estiver encerrado.
+func InitializeServer(port string, routerControler *mux.Router, timeouts config.ServerTimeouts) error {
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           routerControler,
+		ReadTimeout:       timeouts.Read,
+		ReadHeaderTimeout: timeouts.Read,
+		WriteTimeout:      timeouts.Write,
+		IdleTimeout:       timeouts.Idle,
 	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("servidor escutando", slog.String("port", port))
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("erro ao iniciar o servidor: %w", err)
+	case <-ctx.Done():
+	}
+
+	// Um segundo sinal encerra o processo imediatamente, sem esperar o prazo
+	stop()
+	slog.Info("sinal recebido, encerrando o servidor", slog.String("timeout", timeouts.Shutdown.String()))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeouts.Shutdown)
+	defer cancel()
+
+	err := server.Shutdown(shutdownCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		// Fecha as conexões que não terminaram dentro do prazo
+		server.Close()
+		return fmt.Errorf("prazo de encerramento esgotado com requisições em andamento: %w", err)
+	}
+	return err
 }
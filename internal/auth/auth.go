@@ -0,0 +1,354 @@
+// Package auth authenticates the callers of the API, with JWT bearer tokens
+// or API keys, and carries the authenticated principal in the request context.
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// APIKeyHeader is the header carrying an API key.
+const APIKeyHeader = "X-API-Key"
+
+// The ways a principal can authenticate.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+var (
+	// ErrNoCredentials is returned by Authenticate when the request carries
+	// neither a bearer token nor an API key.
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is matched by the errors of credentials that
+	// were sent but are not valid, such as an expired token.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrTokenSigningDisabled is returned by IssueToken when no HMAC secret
+	// is configured to sign the tokens.
+	ErrTokenSigningDisabled = errors.New("token signing is disabled")
+)
+
+// userSubjectPrefix starts the sub claim of the tokens issued to users,
+// followed by the ID of the user.
+const userSubjectPrefix = "user:"
+
+// TokenIssuer is the iss claim of the tokens the API issues to its users.
+// Only the HS256 tokens with this issuer may name a user of the service.
+const TokenIssuer = "recipes-api"
+
+// invalid returns an error matching ErrInvalidCredentials with the reason.
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCredentials, fmt.Sprintf(format, args...))
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject identifies the caller: the sub claim of a token, or
+	// "api_key:<id>" for an API key.
+	Subject string
+	// Name is a display name: the name claim of a token or the name of the key.
+	Name string
+	// Method is how the caller authenticated, MethodJWT or MethodAPIKey.
+	Method string
+	// UserID is the ID of the user the token was issued to, or 0 when the
+	// caller is not a user of the service.
+	UserID int
+	// Role sets the permissions of the caller: the role of the user or of the
+	// API key, or the role claim of a token issued elsewhere, RoleAuthor when
+	// it has none, lowered to the MaxExternalRole of the configuration.
+	Role string
+}
+
+// Anonymous is the principal of the requests without credentials, when
+// anonymous reads are allowed.
+var Anonymous = Principal{Subject: "anonymous", Role: models.RoleViewer}
+
+// principalKey is the context key of the principal.
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Config configures the verification of the bearer tokens. Tokens are
+// rejected when no key is configured.
+type Config struct {
+	// HMACSecret verifies HS256 tokens. Empty disables them.
+	HMACSecret string
+	// RSAPublicKeyFile is a PEM file with the public key verifying RS256 tokens.
+	RSAPublicKeyFile string
+	// JWKSFile is a local JSON Web Key Set whose RSA keys verify RS256 tokens,
+	// picked by the kid header of the token.
+	JWKSFile string
+	// Issuer, when set, must match the iss claim of the tokens issued
+	// elsewhere. It may not be TokenIssuer, which the API keeps for its own.
+	Issuer string
+	// Audience, when set, must match the aud claim, and is set in the tokens
+	// issued to the users.
+	Audience string
+	// MaxExternalRole is the highest role a token issued elsewhere may get
+	// from its role claim; higher roles are lowered to it. Empty means
+	// RoleAuthor.
+	MaxExternalRole string
+	// TokenTTL is how long the tokens issued to the users are valid.
+	TokenTTL time.Duration
+}
+
+// Authenticator authenticates requests.
+type Authenticator struct {
+	secret  []byte
+	rsaKey  *rsa.PublicKey
+	jwks    map[string]*rsa.PublicKey
+	methods []string
+	parser  *jwt.Parser
+	apiKeys models.APIKeyRepository
+	users   models.UserRepository
+
+	issuer          string
+	audience        string
+	maxExternalRole string
+	tokenTTL        time.Duration
+}
+
+// NewAuthenticator creates an Authenticator verifying tokens as set in config,
+// looking the API keys up in apiKeys and the users of the tokens in users.
+func NewAuthenticator(config Config, apiKeys models.APIKeyRepository, users models.UserRepository) (*Authenticator, error) {
+	a := &Authenticator{
+		apiKeys:         apiKeys,
+		users:           users,
+		issuer:          config.Issuer,
+		audience:        config.Audience,
+		maxExternalRole: config.MaxExternalRole,
+		tokenTTL:        config.TokenTTL,
+	}
+
+	if config.Issuer == TokenIssuer {
+		return nil, fmt.Errorf("the issuer %q is reserved for the tokens issued by the API", TokenIssuer)
+	}
+	if a.maxExternalRole == "" {
+		a.maxExternalRole = models.RoleAuthor
+	}
+	if !slices.Contains(models.Roles, a.maxExternalRole) {
+		return nil, fmt.Errorf("unknown maximum role %q for external tokens", a.maxExternalRole)
+	}
+
+	if config.HMACSecret != "" {
+		a.secret = []byte(config.HMACSecret)
+		a.methods = append(a.methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if config.RSAPublicKeyFile != "" {
+		key, err := loadRSAPublicKey(config.RSAPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		a.rsaKey = key
+	}
+	if config.JWKSFile != "" {
+		keys, err := loadJWKS(config.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.jwks = keys
+	}
+	if a.rsaKey != nil || a.jwks != nil {
+		a.methods = append(a.methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(a.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	a.parser = jwt.NewParser(options...)
+
+	return a, nil
+}
+
+// Authenticate returns the principal of the credentials sent with r: an
+// API key in the X-API-Key header, or a bearer token in the Authorization
+// header. It returns ErrNoCredentials when there are none, an error matching
+// ErrInvalidCredentials when they are not valid, and any other error when
+// they could not be checked.
+func (a *Authenticator) Authenticate(r *http.Request) (Principal, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return a.authenticateAPIKey(r.Context(), key)
+	}
+
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return Principal{}, invalid("unsupported authorization scheme, expected Bearer")
+	}
+
+	return a.authenticateToken(r.Context(), strings.TrimSpace(token))
+}
+
+// claims are the claims read from the bearer tokens.
+type claims struct {
+	jwt.RegisteredClaims
+	Name string `json:"name,omitempty"`
+	// Role is read from the tokens not issued to the users of the service,
+	// whose role is stored with them instead.
+	Role string `json:"role,omitempty"`
+}
+
+func (a *Authenticator) authenticateToken(ctx context.Context, token string) (Principal, error) {
+	if len(a.methods) == 0 {
+		return Principal{}, invalid("bearer tokens are not accepted")
+	}
+
+	var c claims
+	parsed, err := a.parser.ParseWithClaims(token, &c, a.key)
+	if err != nil {
+		return Principal{}, invalid("%v", err)
+	}
+	if c.Subject == "" {
+		return Principal{}, invalid("token has no sub claim")
+	}
+
+	// Only the API signs its tokens with TokenIssuer; the HMAC secret is never
+	// shared with the issuers of RS256 tokens
+	issued := parsed.Method.Alg() == jwt.SigningMethodHS256.Alg() && c.Issuer == TokenIssuer
+
+	principal := Principal{Subject: c.Subject, Name: c.Name, Method: MethodJWT}
+	if id, ok := strings.CutPrefix(c.Subject, userSubjectPrefix); ok {
+		if !issued {
+			return Principal{}, invalid("token names a user but was not issued by the API")
+		}
+		return a.authenticateUser(ctx, principal, id)
+	}
+
+	if a.issuer != "" && c.Issuer != a.issuer {
+		return Principal{}, invalid("token has issuer %q, expected %q", c.Issuer, a.issuer)
+	}
+
+	principal.Role = c.Role
+	if principal.Role == "" {
+		principal.Role = models.RoleAuthor
+	}
+	rank := slices.Index(models.Roles, principal.Role)
+	if rank < 0 {
+		return Principal{}, invalid("token has an unknown role %q", c.Role)
+	}
+	if rank > slices.Index(models.Roles, a.maxExternalRole) {
+		principal.Role = a.maxExternalRole
+	}
+
+	return principal, nil
+}
+
+// authenticateUser completes the principal of a token issued to the user
+// with the ID. The user is read on every request, so that a change of role
+// applies at once and a deleted user loses access.
+func (a *Authenticator) authenticateUser(ctx context.Context, principal Principal, id string) (Principal, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil || userID < 1 {
+		return Principal{}, invalid("token has an invalid user subject %q", principal.Subject)
+	}
+
+	user, err := a.users.GetUserByID(ctx, userID)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return Principal{}, invalid("user %d of the token does not exist", userID)
+	case err != nil:
+		return Principal{}, err
+	}
+
+	principal.Name = user.Name
+	principal.UserID = user.ID
+	principal.Role = user.Role
+	return principal, nil
+}
+
+// IssueToken signs an HS256 token for the user, valid for the TokenTTL of
+// the configuration, and returns it with its expiry. It returns
+// ErrTokenSigningDisabled when no HMAC secret is configured.
+func (a *Authenticator) IssueToken(user models.User) (string, time.Time, error) {
+	if a.secret == nil {
+		return "", time.Time{}, ErrTokenSigningDisabled
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(a.tokenTTL)
+	c := claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userSubjectPrefix + strconv.Itoa(user.ID),
+			Issuer:    TokenIssuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Name: user.Name,
+	}
+	if a.audience != "" {
+		c.Audience = jwt.ClaimStrings{a.audience}
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(a.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// key returns the key verifying token, according to its algorithm. The
+// parser only lets through the algorithms with a key configured.
+func (a *Authenticator) key(token *jwt.Token) (any, error) {
+	if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+		return a.secret, nil
+	}
+
+	if kid, ok := token.Header["kid"].(string); ok && a.jwks != nil {
+		key, found := a.jwks[kid]
+		if !found {
+			return nil, fmt.Errorf("unknown key ID %q", kid)
+		}
+		return key, nil
+	}
+	if a.rsaKey == nil {
+		return nil, errors.New("token has no kid header")
+	}
+
+	return a.rsaKey, nil
+}
+
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, key string) (Principal, error) {
+	stored, err := a.apiKeys.GetAPIKeyByHash(ctx, HashAPIKey(key))
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return Principal{}, invalid("unknown api key")
+	case err != nil:
+		return Principal{}, err
+	case stored.Revoked():
+		return Principal{}, invalid("api key was revoked")
+	case !slices.Contains(models.Roles, stored.Role):
+		return Principal{}, invalid("api key has an unknown role %q", stored.Role)
+	}
+
+	return Principal{Subject: "api_key:" + strconv.Itoa(stored.ID), Name: stored.Name, Method: MethodAPIKey, Role: stored.Role}, nil
+}
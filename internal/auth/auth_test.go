@@ -0,0 +1,292 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/keevferreira/recipes-api/internal/database/memory"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+const (
+	secret = "test-secret"
+	// issuer is the issuer of the external tokens.
+	issuer = "https://id.example.com"
+)
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	repositories := memory.NewRepositories(memory.NewStore())
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyFile := filepath.Join(t.TempDir(), "public.pem")
+	if err := os.WriteFile(publicKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := NewAuthenticator(Config{
+		HMACSecret:       secret,
+		RSAPublicKeyFile: publicKeyFile,
+		Issuer:           issuer,
+		TokenTTL:         time.Hour,
+	}, repositories.APIKeys, repositories.Users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiKey := func(role string, revoked bool) string {
+		key, hash, err := NewAPIKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := repositories.APIKeys.CreateAPIKey(ctx, models.APIKey{Name: role, Hash: hash, Role: role})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if revoked {
+			if err := repositories.APIKeys.RevokeAPIKey(ctx, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return key
+	}
+
+	userID, err := repositories.Users.CreateUser(ctx, models.User{Email: "cook@example.com", Name: "Ana", Role: models.RoleEditor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issued, _, err := authenticator.IssueToken(models.User{ID: userID, Name: "Ana"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sign returns a token signed with key, an HMAC secret or an RSA private
+	// key, with the claims, by default valid for an hour and from the
+	// external issuer.
+	sign := func(key any, c jwt.MapClaims) string {
+		if _, ok := c["exp"]; !ok {
+			c["exp"] = time.Now().Add(time.Hour).Unix()
+		}
+		if _, ok := c["iss"]; !ok {
+			c["iss"] = issuer
+		}
+
+		var method jwt.SigningMethod = jwt.SigningMethodHS256
+		if secret, ok := key.(string); ok {
+			key = []byte(secret)
+		} else {
+			method = jwt.SigningMethodRS256
+		}
+		token, err := jwt.NewWithClaims(method, c).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	ownUser := "user:" + strconv.Itoa(userID)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		role    string
+		userID  int
+		err     error
+	}{
+		{"no credentials", nil, "", 0, ErrNoCredentials},
+		{"admin key", map[string]string{APIKeyHeader: apiKey(models.RoleAdmin, false)}, models.RoleAdmin, 0, nil},
+		{"viewer key", map[string]string{APIKeyHeader: apiKey(models.RoleViewer, false)}, models.RoleViewer, 0, nil},
+		{"revoked key", map[string]string{APIKeyHeader: apiKey(models.RoleAdmin, true)}, "", 0, ErrInvalidCredentials},
+		{"key with an unknown role", map[string]string{APIKeyHeader: apiKey("owner", false)}, "", 0, ErrInvalidCredentials},
+		{"unknown key", map[string]string{APIKeyHeader: "rk_unknown"}, "", 0, ErrInvalidCredentials},
+		{"issued token", map[string]string{"Authorization": "Bearer " + issued}, models.RoleEditor, userID, nil},
+		{"lower case scheme", map[string]string{"Authorization": "bearer " + issued}, models.RoleEditor, userID, nil},
+		{"basic scheme", map[string]string{"Authorization": "Basic YTpi"}, "", 0, ErrInvalidCredentials},
+		{"token of a missing user", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "user:999", "iss": TokenIssuer})}, "", 0, ErrInvalidCredentials},
+		{"token of an invalid user", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "user:abc", "iss": TokenIssuer})}, "", 0, ErrInvalidCredentials},
+		{"external token naming a user", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": ownUser})}, "", 0, ErrInvalidCredentials},
+		{"RS256 token naming a user", map[string]string{"Authorization": "Bearer " + sign(rsaKey, jwt.MapClaims{"sub": ownUser, "iss": TokenIssuer})}, "", 0, ErrInvalidCredentials},
+		{"external token", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "service"})}, models.RoleAuthor, 0, nil},
+		{"RS256 external token", map[string]string{"Authorization": "Bearer " + sign(rsaKey, jwt.MapClaims{"sub": "service"})}, models.RoleAuthor, 0, nil},
+		{"external token with a role", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "service", "role": models.RoleViewer})}, models.RoleViewer, 0, nil},
+		{"external token above the maximum role", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "service", "role": models.RoleAdmin})}, models.RoleAuthor, 0, nil},
+		{"external token with an unknown role", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "service", "role": "owner"})}, "", 0, ErrInvalidCredentials},
+		{"external token with the issuer of the API", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "service", "iss": TokenIssuer})}, "", 0, ErrInvalidCredentials},
+		{"token without sub", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{})}, "", 0, ErrInvalidCredentials},
+		{"token with another secret", map[string]string{"Authorization": "Bearer " + sign("other", jwt.MapClaims{"sub": "service"})}, "", 0, ErrInvalidCredentials},
+		{"expired token", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "service", "exp": time.Now().Add(-time.Hour).Unix()})}, "", 0, ErrInvalidCredentials},
+		{"token of another issuer", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "service", "iss": "other"})}, "", 0, ErrInvalidCredentials},
+		{"token without issuer", map[string]string{"Authorization": "Bearer " + sign(secret, jwt.MapClaims{"sub": "service", "iss": ""})}, "", 0, ErrInvalidCredentials},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/recipe", nil)
+			for name, value := range test.headers {
+				r.Header.Set(name, value)
+			}
+
+			principal, err := authenticator.Authenticate(r)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error = %v, want %v", err, test.err)
+			}
+			if principal.Role != test.role || principal.UserID != test.userID {
+				t.Errorf("principal = %+v, want role %q and user %d", principal, test.role, test.userID)
+			}
+		})
+	}
+}
+
+func TestAuthenticateUserRoleChange(t *testing.T) {
+	ctx := context.Background()
+	repositories := memory.NewRepositories(memory.NewStore())
+	authenticator, err := NewAuthenticator(Config{HMACSecret: secret, TokenTTL: time.Hour}, repositories.APIKeys, repositories.Users)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := repositories.Users.CreateUser(ctx, models.User{Email: "cook@example.com", Name: "Ana", Role: models.RoleAuthor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := authenticator.IssueToken(models.User{ID: id, Name: "Ana", Role: models.RoleAuthor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repositories.Users.SetUserRole(ctx, id, models.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", "/recipe", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	principal, err := authenticator.Authenticate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if principal.Role != models.RoleAdmin || principal.Subject != "user:"+strconv.Itoa(id) {
+		t.Errorf("principal = %+v, want the admin user:%d", principal, id)
+	}
+}
+
+func TestMaxExternalRole(t *testing.T) {
+	authenticator, err := NewAuthenticator(Config{HMACSecret: secret, MaxExternalRole: models.RoleEditor}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		role string
+		want string
+	}{
+		{"", models.RoleAuthor},
+		{models.RoleViewer, models.RoleViewer},
+		{models.RoleEditor, models.RoleEditor},
+		{models.RoleAdmin, models.RoleEditor},
+	}
+
+	for _, test := range tests {
+		c := jwt.MapClaims{"sub": "service", "exp": time.Now().Add(time.Hour).Unix()}
+		if test.role != "" {
+			c["role"] = test.role
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := httptest.NewRequest("GET", "/recipe", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		principal, err := authenticator.Authenticate(r)
+		if err != nil || principal.Role != test.want {
+			t.Errorf("role claim %q: principal = %+v, %v, want role %q", test.role, principal, err, test.want)
+		}
+	}
+}
+
+func TestNewAuthenticatorConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"reserved issuer", Config{HMACSecret: secret, Issuer: TokenIssuer}},
+		{"unknown maximum role", Config{HMACSecret: secret, MaxExternalRole: "owner"}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewAuthenticator(test.config, nil, nil); err == nil {
+			t.Errorf("%s: NewAuthenticator succeeded, want an error", test.name)
+		}
+	}
+}
+
+func TestIssueTokenWithoutSecret(t *testing.T) {
+	authenticator, err := NewAuthenticator(Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := authenticator.IssueToken(models.User{ID: 1}); !errors.Is(err, ErrTokenSigningDisabled) {
+		t.Errorf("IssueToken error = %v, want %v", err, ErrTokenSigningDisabled)
+	}
+
+	r := httptest.NewRequest("GET", "/recipe", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	if _, err := authenticator.Authenticate(r); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Authenticate error = %v, want %v", err, ErrInvalidCredentials)
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		hash     string
+		password string
+		want     bool
+	}{
+		{hash, "correct horse", true},
+		{hash, "Correct horse", false},
+		{"", "correct horse", false},
+	}
+
+	for _, test := range tests {
+		got, err := CheckPassword(test.hash, test.password)
+		if err != nil || got != test.want {
+			t.Errorf("CheckPassword(%q, %q) = %t, %v, want %t", test.hash, test.password, got, err, test.want)
+		}
+	}
+}
+
+func TestNewAPIKey(t *testing.T) {
+	key, hash, err := NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key, apiKeyPrefix) || len(key) == len(apiKeyPrefix) {
+		t.Errorf("key = %q, want the prefix %q", key, apiKeyPrefix)
+	}
+	if hash != HashAPIKey(key) || hash == key {
+		t.Errorf("hash = %q, want the hash of the key", hash)
+	}
+}
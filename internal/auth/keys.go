@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// apiKeyPrefix marks the API keys of the service, so that leaked keys are
+// easy to recognise.
+const apiKeyPrefix = "rk_"
+
+// NewAPIKey generates a random API key, to be shown once to its owner, and
+// the hash to store in its place.
+func NewAPIKey() (key string, hash string, err error) {
+	var secret [32]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return "", "", err
+	}
+
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret[:])
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash of key. The keys are long
+// random strings, so a fast unsalted hash is enough to keep them secret.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadRSAPublicKey reads a PEM encoded RSA public key.
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading the RSA public key: %w", err)
+	}
+
+	key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing the RSA public key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// jwks is a JSON Web Key Set, as defined by RFC 7517.
+type jwks struct {
+	Keys []struct {
+		KeyType string `json:"kty"`
+		KeyID   string `json:"kid"`
+		Use     string `json:"use"`
+		N       string `json:"n"`
+		E       string `json:"e"`
+	} `json:"keys"`
+}
+
+// loadJWKS reads the RSA signing keys of a JSON Web Key Set, by key ID.
+// Keys of other types or uses are skipped.
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading the JWKS: %w", err)
+	}
+
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parsing the JWKS %s: %w", path, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.KeyType != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the modulus of key %q of the JWKS: %w", k.KeyID, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the exponent of key %q of the JWKS: %w", k.KeyID, err)
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("key %q of the JWKS has an invalid exponent", k.KeyID)
+		}
+		keys[k.KeyID] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("the JWKS %s has no RSA signing keys", path)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordCost is the bcrypt cost of the password hashes.
+const passwordCost = 12
+
+// dummyHash is compared against when a login names an unknown user, so that
+// the response takes as long as for a wrong password and does not reveal
+// which e-mails have an account.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("recipes-api"), passwordCost)
+
+// HashPassword returns the bcrypt hash of password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches hash. An empty hash, as for
+// an unknown user, never matches but costs the same as a real comparison.
+func CheckPassword(hash string, password string) (bool, error) {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false, nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
@@ -0,0 +1,70 @@
+package auth
+
+import "github.com/keevferreira/recipes-api/internal/models"
+
+// Permission allows an operation on a kind of resource. The routes of the
+// API each require one.
+type Permission string
+
+// The permissions of the API.
+const (
+	// PermRecipesRead allows reading the recipes.
+	PermRecipesRead Permission = "recipes:read"
+	// PermRecipesWrite allows creating recipes and changing one's own.
+	PermRecipesWrite Permission = "recipes:write"
+	// PermRecipesManage allows changing the recipes of every author.
+	PermRecipesManage Permission = "recipes:manage"
+	// PermCatalogRead allows reading the shared catalogs of ingredients
+	// and categories, and converting units with them.
+	PermCatalogRead Permission = "catalog:read"
+	// PermCatalogCreate allows adding ingredients and categories.
+	PermCatalogCreate Permission = "catalog:create"
+	// PermCatalogManage allows renaming and deleting ingredients and
+	// categories, which changes every recipe using them.
+	PermCatalogManage Permission = "catalog:manage"
+	// PermPlanningRead allows reading the pantry and the shopping lists.
+	PermPlanningRead Permission = "planning:read"
+	// PermPlanningWrite allows changing the pantry and the shopping lists.
+	PermPlanningWrite Permission = "planning:write"
+	// PermPlanningManage allows reading and changing the shopping lists of
+	// every principal, not only one's own.
+	PermPlanningManage Permission = "planning:manage"
+	// PermUsersManage allows reading the users and assigning their roles.
+	PermUsersManage Permission = "users:manage"
+	// PermStatusRead allows reading the detailed status report of the service.
+	PermStatusRead Permission = "status:read"
+)
+
+// rolePermissions is the permission matrix: the permissions granted to each
+// role. Every role has the permissions of the roles before it.
+var rolePermissions = func() map[string]map[Permission]bool {
+	grants := []struct {
+		role        string
+		permissions []Permission
+	}{
+		{models.RoleViewer, []Permission{PermRecipesRead, PermCatalogRead, PermPlanningRead}},
+		{models.RoleAuthor, []Permission{PermRecipesWrite, PermCatalogCreate, PermPlanningWrite}},
+		{models.RoleEditor, []Permission{PermRecipesManage, PermCatalogManage}},
+		{models.RoleAdmin, []Permission{PermUsersManage, PermStatusRead, PermPlanningManage}},
+	}
+
+	matrix := make(map[string]map[Permission]bool, len(grants))
+	granted := make(map[Permission]bool)
+	for _, grant := range grants {
+		for _, permission := range grant.permissions {
+			granted[permission] = true
+		}
+
+		matrix[grant.role] = make(map[Permission]bool, len(granted))
+		for permission := range granted {
+			matrix[grant.role][permission] = true
+		}
+	}
+
+	return matrix
+}()
+
+// Can reports whether the role of the principal grants the permission.
+func (p Principal) Can(permission Permission) bool {
+	return rolePermissions[p.Role][permission]
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+func TestCan(t *testing.T) {
+	permissions := []Permission{
+		PermRecipesRead, PermCatalogRead, PermPlanningRead,
+		PermRecipesWrite, PermCatalogCreate, PermPlanningWrite,
+		PermRecipesManage, PermCatalogManage,
+		PermUsersManage, PermStatusRead, PermPlanningManage,
+	}
+
+	// granted is how many of the permissions above each role has, since
+	// every role has the permissions of the roles before it.
+	tests := []struct {
+		role    string
+		granted int
+	}{
+		{models.RoleViewer, 3},
+		{models.RoleAuthor, 6},
+		{models.RoleEditor, 8},
+		{models.RoleAdmin, 11},
+		{"owner", 0},
+		{"", 0},
+	}
+
+	for _, test := range tests {
+		principal := Principal{Role: test.role}
+		for i, permission := range permissions {
+			if want := i < test.granted; principal.Can(permission) != want {
+				t.Errorf("role %q Can(%s) = %t, want %t", test.role, permission, !want, want)
+			}
+		}
+	}
+
+	if Anonymous.Can(PermRecipesWrite) || !Anonymous.Can(PermRecipesRead) {
+		t.Errorf("Anonymous should only read")
+	}
+}
@@ -2,11 +2,23 @@ package database
 
 import (
 	"database/sql"
+	"fmt"
 
-	postgres "github.com/keevferreira/recipes-api/internal/database/postgres"
+	"github.com/keevferreira/recipes-api/internal/database/memory"
+	"github.com/keevferreira/recipes-api/internal/database/migrate"
+	"github.com/keevferreira/recipes-api/internal/database/migrations"
+	"github.com/keevferreira/recipes-api/internal/database/sqlstore"
+	"github.com/keevferreira/recipes-api/internal/models"
 	"github.com/keevferreira/recipes-api/internal/utils"
 )
 
+// Drivers de armazenamento suportados, selecionados por DB_DRIVER.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
 type Database interface {
 	Connect(connectionString string) (*sql.DB, error)
 	Disconnect(db *sql.DB) error
@@ -14,12 +26,59 @@ type Database interface {
 
 var DB *sql.DB
 
-func Connect(connectionString string) {
+// driver guarda o driver escolhido em Connect.
+var driver string
+
+// Connect prepara o armazenamento do driver informado. Para o driver em
+// memória não há conexão a abrir.
+func Connect(driverName string, connectionString string) error {
 	var err error
-	DB, err = postgres.ConnectToPostgresDB(connectionString)
-	utils.TreatNilObjectError(err, "Não foi possível conectar no banco de dados")
+	switch driverName {
+	case DriverPostgres:
+		DB, err = sqlstore.ConnectToPostgresDB(connectionString)
+	case DriverSQLite:
+		DB, err = sqlstore.ConnectToSQLiteDB(connectionString)
+	case DriverMemory:
+	default:
+		err = fmt.Errorf("driver de banco de dados desconhecido: %s", driverName)
+	}
+	if err != nil {
+		return utils.WrapError(err, "Não foi possível conectar no banco de dados")
+	}
+	driver = driverName
+	return nil
+}
+
+// Disconnect fecha o pool de conexões aberto em Connect. Para o driver em
+// memória não há nada a fechar.
+func Disconnect() error {
+	if DB == nil {
+		return nil
+	}
+	if driver == DriverPostgres {
+		return sqlstore.DisconnectPostgresDB(DB)
+	}
+	return DB.Close()
+}
+
+// NewRepositories cria os repositórios do driver escolhido em Connect.
+func NewRepositories() *models.Repositories {
+	switch driver {
+	case DriverSQLite:
+		return sqlstore.NewRepositories(DB, sqlstore.SQLite)
+	case DriverMemory:
+		return memory.NewRepositories(memory.NewStore())
+	}
+	return sqlstore.NewRepositories(DB, sqlstore.Postgres)
 }
 
-func Disconnect(db *sql.DB) {
-	postgres.DisconnectPostgresDB(DB)
+// NewMigrator cria o executor de migrações do driver escolhido em Connect.
+func NewMigrator() (*migrate.Migrator, error) {
+	switch driver {
+	case DriverPostgres:
+		return migrate.New(DB, migrate.Postgres, migrations.Files)
+	case DriverSQLite:
+		return migrate.New(DB, migrate.SQLite, migrations.SQLite())
+	}
+	return nil, fmt.Errorf("o driver %s não usa migrações", driver)
 }
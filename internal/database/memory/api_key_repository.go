@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// APIKeyRepository is the in-memory implementation of models.APIKeyRepository.
+type APIKeyRepository struct {
+	store *Store
+}
+
+// NewAPIKeyRepository creates an APIKeyRepository backed by store.
+func NewAPIKeyRepository(store *Store) *APIKeyRepository {
+	return &APIKeyRepository{store: store}
+}
+
+// GetAPIKeyByHash retrieves the API key with the hash from the store.
+func (ar *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	ar.store.mu.RLock()
+	defer ar.store.mu.RUnlock()
+
+	for _, key := range ar.store.apiKeys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+
+	return models.APIKey{}, fmt.Errorf("api key %w", models.ErrNotFound)
+}
+
+// CreateAPIKey stores an API key in the store.
+func (ar *APIKeyRepository) CreateAPIKey(ctx context.Context, key models.APIKey) (int, error) {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	ar.store.nextAPIKeyID++
+	key.ID = ar.store.nextAPIKeyID
+	key.CreatedAt = time.Now()
+	key.RevokedAt = nil
+	ar.store.apiKeys[key.ID] = key
+
+	return key.ID, nil
+}
+
+// RevokeAPIKey revokes an API key in the store.
+func (ar *APIKeyRepository) RevokeAPIKey(ctx context.Context, id int) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	key, ok := ar.store.apiKeys[id]
+	if !ok {
+		return models.NewNotFoundError("api key", id)
+	}
+
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		ar.store.apiKeys[id] = key
+	}
+
+	return nil
+}
+
+// SetAPIKeyRole changes the role of an API key in the store.
+func (ar *APIKeyRepository) SetAPIKeyRole(ctx context.Context, id int, role string) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	key, ok := ar.store.apiKeys[id]
+	if !ok {
+		return models.NewNotFoundError("api key", id)
+	}
+
+	key.Role = role
+	ar.store.apiKeys[id] = key
+
+	return nil
+}
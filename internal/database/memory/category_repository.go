@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// CategoryRepository is the in-memory implementation of models.CategoryRepository.
+type CategoryRepository struct {
+	store *Store
+}
+
+// NewCategoryRepository creates a CategoryRepository backed by store.
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+func (cr *CategoryRepository) GetCategoryByID(ctx context.Context, id int) (models.Category, error) {
+	cr.store.mu.RLock()
+	defer cr.store.mu.RUnlock()
+
+	category, ok := cr.store.categories[id]
+	if !ok {
+		return models.Category{}, models.NewNotFoundError("category", id)
+	}
+
+	return category, nil
+}
+
+// GetCategoriesByIDs retrieves the categories with the given IDs from the store.
+func (cr *CategoryRepository) GetCategoriesByIDs(ctx context.Context, ids []int) ([]models.Category, error) {
+	cr.store.mu.RLock()
+	defer cr.store.mu.RUnlock()
+
+	var categories []models.Category
+	for _, id := range ids {
+		if category, ok := cr.store.categories[id]; ok {
+			categories = append(categories, category)
+		}
+	}
+
+	return categories, nil
+}
+
+func (cr *CategoryRepository) UpdateCategoryByID(ctx context.Context, id int, updatedCategory models.Category) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	category, ok := cr.store.categories[id]
+	if !ok {
+		return models.NewNotFoundError("category", id)
+	}
+
+	category.Name = updatedCategory.Name
+	category.Description = updatedCategory.Description
+	category.UpdatedAt = time.Now()
+	cr.store.categories[id] = category
+
+	return nil
+}
+
+func (cr *CategoryRepository) DeleteCategoryByID(ctx context.Context, id int) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	for recipeID, categoryIDs := range cr.store.recipeCategories {
+		for _, categoryID := range categoryIDs {
+			if categoryID == id {
+				return models.NewConflictError("category with ID %d is used by recipe with ID %d", id, recipeID)
+			}
+		}
+	}
+
+	if _, ok := cr.store.categories[id]; !ok {
+		return models.NewNotFoundError("category", id)
+	}
+
+	delete(cr.store.categories, id)
+
+	return nil
+}
+
+// GetAllCategories retrieves a page of the categories from the store.
+func (cr *CategoryRepository) GetAllCategories(ctx context.Context, options models.ListOptions) ([]models.Category, models.PageInfo, error) {
+	cr.store.mu.RLock()
+	defer cr.store.mu.RUnlock()
+
+	var categories []models.Category
+	for _, category := range cr.store.categories {
+		categories = append(categories, category)
+	}
+
+	categories, info := paginate(categories, options, func(c models.Category) int { return c.ID }, categoryLess(options.Sort))
+
+	return categories, info, nil
+}
+
+// categoryLess returns the ordering of categories for a sort key.
+func categoryLess(key string) func(a, b models.Category) bool {
+	switch key {
+	case models.SortByName:
+		return func(a, b models.Category) bool { return a.Name < b.Name }
+	case models.SortByCreatedAt:
+		return func(a, b models.Category) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	default:
+		return func(a, b models.Category) bool { return false }
+	}
+}
+
+func (cr *CategoryRepository) CreateCategory(ctx context.Context, category models.Category) (int, error) {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	cr.store.nextCategoryID++
+	category.ID = cr.store.nextCategoryID
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = category.CreatedAt
+	cr.store.categories[category.ID] = category
+
+	return category.ID, nil
+}
+
+func (cr *CategoryRepository) GetCategoriesByRecipeID(ctx context.Context, recipeID int) ([]models.Category, error) {
+	cr.store.mu.RLock()
+	defer cr.store.mu.RUnlock()
+
+	return cr.store.categoriesByRecipeID(recipeID), nil
+}
+
+func (cr *CategoryRepository) UpdateCategoriesByRecipeID(ctx context.Context, recipeID int, updatedCategories []models.Category) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	if err := cr.store.checkRecipe(recipeID, len(updatedCategories)); err != nil {
+		return err
+	}
+	if err := cr.store.checkCategories(updatedCategories); err != nil {
+		return err
+	}
+
+	cr.store.setCategoriesByRecipeID(recipeID, updatedCategories)
+
+	return nil
+}
+
+func (cr *CategoryRepository) DeleteCategoryByRecipeID(ctx context.Context, recipeID int) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	delete(cr.store.recipeCategories, recipeID)
+
+	return nil
+}
+
+// categoriesByRecipeID returns the categories linked to a recipe. The caller must hold the lock.
+func (s *Store) categoriesByRecipeID(recipeID int) []models.Category {
+	var categories []models.Category
+	for _, categoryID := range s.recipeCategories[recipeID] {
+		categories = append(categories, s.categories[categoryID])
+	}
+
+	return categories
+}
+
+// checkCategories reports an error if any category does not exist. The caller must hold the lock.
+func (s *Store) checkCategories(categories []models.Category) error {
+	for _, category := range categories {
+		if _, ok := s.categories[category.ID]; !ok {
+			return missingReference("category", category.ID)
+		}
+	}
+
+	return nil
+}
+
+// setCategoriesByRecipeID replaces the categories linked to a recipe. The caller must hold the lock.
+func (s *Store) setCategoriesByRecipeID(recipeID int, categories []models.Category) {
+	if len(categories) == 0 {
+		delete(s.recipeCategories, recipeID)
+		return
+	}
+
+	categoryIDs := make([]int, 0, len(categories))
+	for _, category := range categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+	s.recipeCategories[recipeID] = categoryIDs
+}
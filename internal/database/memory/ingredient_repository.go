@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// IngredientRepository is the in-memory implementation of models.IngredientRepository.
+type IngredientRepository struct {
+	store *Store
+}
+
+// NewIngredientRepository creates an IngredientRepository backed by store.
+func NewIngredientRepository(store *Store) *IngredientRepository {
+	return &IngredientRepository{store: store}
+}
+
+func (ir *IngredientRepository) GetIngredientByID(ctx context.Context, id int) (models.Ingredient, error) {
+	ir.store.mu.RLock()
+	defer ir.store.mu.RUnlock()
+
+	ingredient, ok := ir.store.ingredients[id]
+	if !ok {
+		return models.Ingredient{}, models.NewNotFoundError("ingredient", id)
+	}
+
+	return ingredient, nil
+}
+
+// GetIngredientsByIDs retrieves the ingredients with the given IDs from the store.
+func (ir *IngredientRepository) GetIngredientsByIDs(ctx context.Context, ids []int) ([]models.Ingredient, error) {
+	ir.store.mu.RLock()
+	defer ir.store.mu.RUnlock()
+
+	var ingredients []models.Ingredient
+	for _, id := range ids {
+		if ingredient, ok := ir.store.ingredients[id]; ok {
+			ingredients = append(ingredients, ingredient)
+		}
+	}
+
+	return ingredients, nil
+}
+
+func (ir *IngredientRepository) UpdateIngredientByID(ctx context.Context, id int, updatedIngredient models.Ingredient) error {
+	ir.store.mu.Lock()
+	defer ir.store.mu.Unlock()
+
+	ingredient, ok := ir.store.ingredients[id]
+	if !ok {
+		return models.NewNotFoundError("ingredient", id)
+	}
+
+	ingredient.Name = updatedIngredient.Name
+	ingredient.Density = updatedIngredient.Density
+	ingredient.Aisle = updatedIngredient.Aisle
+	ingredient.UpdatedAt = time.Now()
+	ir.store.ingredients[id] = ingredient
+
+	return nil
+}
+
+func (ir *IngredientRepository) DeleteIngredientByID(ctx context.Context, id int) error {
+	ir.store.mu.Lock()
+	defer ir.store.mu.Unlock()
+
+	for recipeID, lines := range ir.store.recipeIngredients {
+		for _, line := range lines {
+			if line.IngredientID == id {
+				return models.NewConflictError("ingredient with ID %d is used by recipe with ID %d", id, recipeID)
+			}
+		}
+	}
+
+	for listID, list := range ir.store.shoppingLists {
+		for _, item := range list.Items {
+			if item.IngredientID == id {
+				return models.NewConflictError("ingredient with ID %d is used by shopping list with ID %d", id, listID)
+			}
+		}
+	}
+
+	for key := range ir.store.pantry {
+		if key.ingredientID == id {
+			return models.NewConflictError("ingredient with ID %d is in the pantry", id)
+		}
+	}
+
+	if _, ok := ir.store.ingredients[id]; !ok {
+		return models.NewNotFoundError("ingredient", id)
+	}
+
+	delete(ir.store.ingredients, id)
+
+	return nil
+}
+
+// GetAllIngredients retrieves a page of the ingredients matching filter from the store.
+func (ir *IngredientRepository) GetAllIngredients(ctx context.Context, filter models.IngredientFilter, options models.ListOptions) ([]models.Ingredient, models.PageInfo, error) {
+	ir.store.mu.RLock()
+	defer ir.store.mu.RUnlock()
+
+	var ingredients []models.Ingredient
+	for _, ingredient := range ir.store.ingredients {
+		if filter.Aisle == "" || ingredient.Aisle == filter.Aisle {
+			ingredients = append(ingredients, ingredient)
+		}
+	}
+
+	ingredients, info := paginate(ingredients, options, func(i models.Ingredient) int { return i.ID }, ingredientLess(options.Sort))
+
+	return ingredients, info, nil
+}
+
+// ingredientLess returns the ordering of ingredients for a sort key.
+func ingredientLess(key string) func(a, b models.Ingredient) bool {
+	switch key {
+	case models.SortByName:
+		return func(a, b models.Ingredient) bool { return a.Name < b.Name }
+	case models.SortByCreatedAt:
+		return func(a, b models.Ingredient) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	default:
+		return func(a, b models.Ingredient) bool { return false }
+	}
+}
+
+// CreateIngredient creates a new ingredient in the store.
+func (ir *IngredientRepository) CreateIngredient(ctx context.Context, ingredient models.Ingredient) (int, error) {
+	ir.store.mu.Lock()
+	defer ir.store.mu.Unlock()
+
+	ir.store.nextIngredientID++
+	ingredient.ID = ir.store.nextIngredientID
+	ingredient.CreatedAt = time.Now()
+	ingredient.UpdatedAt = ingredient.CreatedAt
+	ir.store.ingredients[ingredient.ID] = ingredient
+
+	return ingredient.ID, nil
+}
+
+// GetIngredientsByRecipeID retrieves the ingredient lines of a recipe from the store.
+func (ir *IngredientRepository) GetIngredientsByRecipeID(ctx context.Context, recipeID int) ([]models.RecipeIngredient, error) {
+	ir.store.mu.RLock()
+	defer ir.store.mu.RUnlock()
+
+	return ir.store.ingredientsByRecipeID(recipeID), nil
+}
+
+// UpdateIngredientsByRecipeID replaces the ingredient lines of a recipe in the store.
+func (ir *IngredientRepository) UpdateIngredientsByRecipeID(ctx context.Context, recipeID int, updatedIngredients []models.RecipeIngredient) error {
+	ir.store.mu.Lock()
+	defer ir.store.mu.Unlock()
+
+	if err := ir.store.checkRecipe(recipeID, len(updatedIngredients)); err != nil {
+		return err
+	}
+	if err := ir.store.checkIngredients(updatedIngredients); err != nil {
+		return err
+	}
+
+	ir.store.setIngredientsByRecipeID(recipeID, updatedIngredients)
+
+	return nil
+}
+
+// DeleteIngredientsByRecipeID deletes the ingredient lines of a recipe from the store.
+// The ingredients themselves stay in the catalog, since other recipes may use them.
+func (ir *IngredientRepository) DeleteIngredientsByRecipeID(ctx context.Context, recipeID int) error {
+	ir.store.mu.Lock()
+	defer ir.store.mu.Unlock()
+
+	delete(ir.store.recipeIngredients, recipeID)
+
+	return nil
+}
+
+// ingredientsByRecipeID returns the ingredient lines of a recipe with the
+// catalog name of each ingredient. The caller must hold the lock.
+func (s *Store) ingredientsByRecipeID(recipeID int) []models.RecipeIngredient {
+	var ingredients []models.RecipeIngredient
+	for _, line := range s.recipeIngredients[recipeID] {
+		line.Name = s.ingredients[line.IngredientID].Name
+		ingredients = append(ingredients, line)
+	}
+
+	return ingredients
+}
+
+// checkIngredients reports an error if any ingredient does not exist. The caller must hold the lock.
+func (s *Store) checkIngredients(ingredients []models.RecipeIngredient) error {
+	for _, ingredient := range ingredients {
+		if _, ok := s.ingredients[ingredient.IngredientID]; !ok {
+			return missingReference("ingredient", ingredient.IngredientID)
+		}
+	}
+
+	return nil
+}
+
+// setIngredientsByRecipeID replaces the ingredient lines of a recipe. The caller must hold the lock.
+func (s *Store) setIngredientsByRecipeID(recipeID int, ingredients []models.RecipeIngredient) {
+	if len(ingredients) == 0 {
+		delete(s.recipeIngredients, recipeID)
+		return
+	}
+
+	lines := make([]models.RecipeIngredient, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		ingredient.Name = ""
+		lines = append(lines, ingredient)
+	}
+	s.recipeIngredients[recipeID] = lines
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// paginate sorts items with less, breaking ties by ID, and returns the page
+// selected by options along with its information.
+func paginate[T any](items []T, options models.ListOptions, id func(T) int, less func(a, b T) bool) ([]T, models.PageInfo) {
+	before := func(a, b T) bool {
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return id(a) < id(b)
+	}
+
+	desc := options.Desc != options.Backwards()
+	sort.Slice(items, func(i, j int) bool {
+		if desc {
+			return before(items[j], items[i])
+		}
+		return before(items[i], items[j])
+	})
+
+	start := options.Offset
+	if options.Cursor != nil {
+		// Like the SQL backends, a cursor on a deleted item selects nothing
+		start = len(items)
+		for i, item := range items {
+			if id(item) == options.Cursor.ID {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	start = min(start, len(items))
+	end := min(start+options.Limit+1, len(items))
+
+	return models.Page(options, items[start:end], len(items))
+}
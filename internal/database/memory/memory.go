@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// Store keeps every recipe, ingredient and category in memory. It is safe for
+// concurrent use and mirrors the behaviour of the PostgreSQL schema, including
+// the foreign keys between recipes and their ingredients and categories.
+type Store struct {
+	mu sync.RWMutex
+
+	recipes     map[int]models.Recipe
+	ingredients map[int]models.Ingredient
+	categories  map[int]models.Category
+
+	recipeIngredients map[int][]models.RecipeIngredient
+	recipeCategories  map[int][]int
+	recipeSteps       map[int][]models.Step
+	shoppingLists     map[int]models.ShoppingList
+	pantry            map[pantryKey]models.PantryItem
+	apiKeys           map[int]models.APIKey
+	users             map[int]models.User
+
+	nextRecipeID     int
+	nextIngredientID int
+	nextCategoryID   int
+	nextStepID       int
+	nextListID       int
+	nextListItemID   int
+	nextAPIKeyID     int
+	nextUserID       int
+}
+
+// NewStore creates an empty Store.
+func NewStore() *Store {
+	return &Store{
+		recipes:           make(map[int]models.Recipe),
+		ingredients:       make(map[int]models.Ingredient),
+		categories:        make(map[int]models.Category),
+		recipeIngredients: make(map[int][]models.RecipeIngredient),
+		recipeCategories:  make(map[int][]int),
+		recipeSteps:       make(map[int][]models.Step),
+		shoppingLists:     make(map[int]models.ShoppingList),
+		pantry:            make(map[pantryKey]models.PantryItem),
+		apiKeys:           make(map[int]models.APIKey),
+		users:             make(map[int]models.User),
+	}
+}
+
+// NewRepositories creates the in-memory implementation of every repository
+// sharing a single Store.
+func NewRepositories(store *Store) *models.Repositories {
+	return &models.Repositories{
+		Recipes:       NewRecipeRepository(store),
+		Ingredients:   NewIngredientRepository(store),
+		Categories:    NewCategoryRepository(store),
+		ShoppingLists: NewShoppingListRepository(store),
+		Pantry:        NewPantryRepository(store),
+		APIKeys:       NewAPIKeyRepository(store),
+		Users:         NewUserRepository(store),
+	}
+}
+
+// missingReference returns the error of input that references a record that
+// does not exist, matching the foreign key violations of the SQL backends.
+func missingReference(resource string, id int) error {
+	return models.NewValidationError(models.FieldError{
+		Code:    models.FieldNotFound,
+		Message: fmt.Sprintf("%s with ID %d not found", resource, id),
+	})
+}
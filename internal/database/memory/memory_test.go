@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/keevferreira/recipes-api/internal/database/memory"
+	"github.com/keevferreira/recipes-api/internal/database/repotest"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *models.Repositories {
+		return memory.NewRepositories(memory.NewStore())
+	})
+}
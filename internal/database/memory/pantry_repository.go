@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// pantryKey identifies an item of the pantry of an owner.
+type pantryKey struct {
+	owner        string
+	ingredientID int
+}
+
+// PantryRepository is the in-memory implementation of models.PantryRepository.
+type PantryRepository struct {
+	store *Store
+}
+
+// NewPantryRepository creates a PantryRepository backed by store.
+func NewPantryRepository(store *Store) *PantryRepository {
+	return &PantryRepository{store: store}
+}
+
+// GetPantryItems retrieves every item in the pantry of owner from the store.
+func (pr *PantryRepository) GetPantryItems(ctx context.Context, owner string) ([]models.PantryItem, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	var items []models.PantryItem
+	for key, item := range pr.store.pantry {
+		if key.owner != owner {
+			continue
+		}
+
+		item.Name = pr.store.ingredients[item.IngredientID].Name
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	return items, nil
+}
+
+// SetPantryItem adds an ingredient to the pantry of owner or replaces its
+// quantity.
+func (pr *PantryRepository) SetPantryItem(ctx context.Context, owner string, item models.PantryItem) error {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	if _, ok := pr.store.ingredients[item.IngredientID]; !ok {
+		return models.NewNotFoundError("ingredient", item.IngredientID)
+	}
+
+	item.Name = ""
+	pr.store.pantry[pantryKey{owner, item.IngredientID}] = item
+
+	return nil
+}
+
+// DeletePantryItem removes an ingredient from the pantry of owner.
+func (pr *PantryRepository) DeletePantryItem(ctx context.Context, owner string, ingredientID int) error {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	delete(pr.store.pantry, pantryKey{owner, ingredientID})
+
+	return nil
+}
+
+// FindCookableRecipes matches the required ingredient lines of every recipe
+// against the pantry of owner.
+func (pr *PantryRepository) FindCookableRecipes(ctx context.Context, owner string, maxMissing int, limit int) ([]models.CookableRecipe, error) {
+	pr.store.mu.RLock()
+	defer pr.store.mu.RUnlock()
+
+	var recipes []models.CookableRecipe
+	for recipeID := range pr.store.recipeIngredients {
+		recipe := models.CookableRecipe{
+			RecipeID: recipeID,
+			Title:    pr.store.recipes[recipeID].Title,
+			Missing:  []models.RecipeIngredient{},
+		}
+
+		for _, line := range pr.store.ingredientsByRecipeID(recipeID) {
+			if line.Optional {
+				continue
+			}
+
+			recipe.TotalIngredients++
+			item, ok := pr.store.pantry[pantryKey{owner, line.IngredientID}]
+			if !ok || !item.Covers(line) {
+				recipe.Missing = append(recipe.Missing, line)
+			}
+		}
+
+		if recipe.TotalIngredients > 0 {
+			recipes = append(recipes, recipe)
+		}
+	}
+
+	recipes = models.RankCookableRecipes(recipes, maxMissing)
+	if len(recipes) > limit {
+		recipes = recipes[:limit]
+	}
+
+	return recipes, nil
+}
@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// RecipeRepository is the in-memory implementation of models.RecipeRepository.
+type RecipeRepository struct {
+	store *Store
+}
+
+// NewRecipeRepository creates a RecipeRepository backed by store.
+func NewRecipeRepository(store *Store) *RecipeRepository {
+	return &RecipeRepository{store: store}
+}
+
+// GetRecipeByID retrieves a recipe by its ID from the store.
+func (rr *RecipeRepository) GetRecipeByID(ctx context.Context, id int) (models.Recipe, error) {
+	rr.store.mu.RLock()
+	defer rr.store.mu.RUnlock()
+
+	recipe, ok := rr.store.recipes[id]
+	if !ok {
+		return models.Recipe{}, models.NewNotFoundError("recipe", id)
+	}
+
+	recipe = rr.store.loadRecipe(recipe)
+	recipe.Steps = rr.store.stepsByRecipeID(id)
+
+	return recipe, nil
+}
+
+// UpdateRecipeByID updates a recipe by its ID in the store.
+func (rr *RecipeRepository) UpdateRecipeByID(ctx context.Context, id int, updatedRecipe models.Recipe) error {
+	rr.store.mu.Lock()
+	defer rr.store.mu.Unlock()
+
+	if err := rr.store.checkIngredients(updatedRecipe.Ingredients); err != nil {
+		return err
+	}
+	if err := rr.store.checkCategories(updatedRecipe.Categories); err != nil {
+		return err
+	}
+	if err := rr.store.checkSteps(updatedRecipe.Steps); err != nil {
+		return err
+	}
+
+	recipe, ok := rr.store.recipes[id]
+	if !ok {
+		return models.NewNotFoundError("recipe", id)
+	}
+	if err := rr.store.checkStepIDs(id, updatedRecipe.Steps); err != nil {
+		return err
+	}
+
+	recipe.Title = updatedRecipe.Title
+	recipe.Description = updatedRecipe.Description
+	recipe.PrepTime = updatedRecipe.PrepTime
+	recipe.Servings = updatedRecipe.Servings
+	recipe.Difficulty = updatedRecipe.Difficulty
+	recipe.UpdatedAt = time.Now()
+	rr.store.recipes[id] = recipe
+
+	rr.store.setIngredientsByRecipeID(id, updatedRecipe.Ingredients)
+	rr.store.setCategoriesByRecipeID(id, updatedRecipe.Categories)
+	rr.store.setStepsByRecipeID(id, updatedRecipe.Steps)
+
+	return nil
+}
+
+// DeleteRecipeByID deletes a recipe by its ID from the store.
+func (rr *RecipeRepository) DeleteRecipeByID(ctx context.Context, id int) error {
+	rr.store.mu.Lock()
+	defer rr.store.mu.Unlock()
+
+	if _, ok := rr.store.recipes[id]; !ok {
+		return models.NewNotFoundError("recipe", id)
+	}
+
+	delete(rr.store.recipeIngredients, id)
+	delete(rr.store.recipeCategories, id)
+	delete(rr.store.recipeSteps, id)
+	delete(rr.store.recipes, id)
+
+	return nil
+}
+
+// GetAllRecipes retrieves a page of the recipes matching filter from the store.
+func (rr *RecipeRepository) GetAllRecipes(ctx context.Context, filter models.RecipeFilter, includes models.RecipeIncludes, options models.ListOptions) ([]models.Recipe, models.PageInfo, error) {
+	rr.store.mu.RLock()
+	defer rr.store.mu.RUnlock()
+
+	var recipes []models.Recipe
+	for _, recipe := range rr.store.recipes {
+		if rr.store.matchRecipe(recipe, filter) {
+			recipes = append(recipes, recipe)
+		}
+	}
+
+	recipes, info := paginate(recipes, options, func(r models.Recipe) int { return r.ID }, recipeLess(options.Sort))
+	for i := range recipes {
+		if includes.Ingredients {
+			recipes[i].Ingredients = rr.store.ingredientsByRecipeID(recipes[i].ID)
+		}
+		if includes.Categories {
+			recipes[i].Categories = rr.store.categoriesByRecipeID(recipes[i].ID)
+		}
+	}
+
+	return recipes, info, nil
+}
+
+// CreateRecipe creates a new recipe in the store.
+func (rr *RecipeRepository) CreateRecipe(ctx context.Context, recipe models.Recipe) (int, error) {
+	rr.store.mu.Lock()
+	defer rr.store.mu.Unlock()
+
+	if err := rr.store.checkIngredients(recipe.Ingredients); err != nil {
+		return 0, err
+	}
+	if err := rr.store.checkCategories(recipe.Categories); err != nil {
+		return 0, err
+	}
+	if err := rr.store.checkSteps(recipe.Steps); err != nil {
+		return 0, err
+	}
+
+	rr.store.nextRecipeID++
+	id := rr.store.nextRecipeID
+
+	rr.store.setIngredientsByRecipeID(id, recipe.Ingredients)
+	rr.store.setCategoriesByRecipeID(id, recipe.Categories)
+	rr.store.setStepsByRecipeID(id, recipe.Steps)
+
+	recipe.ID = id
+	recipe.Ingredients = nil
+	recipe.Categories = nil
+	recipe.Steps = nil
+	recipe.CreatedAt = time.Now()
+	recipe.UpdatedAt = recipe.CreatedAt
+	rr.store.recipes[id] = recipe
+
+	return id, nil
+}
+
+// loadRecipe fills in the ingredients and categories of a stored recipe. The caller must hold the lock.
+func (s *Store) loadRecipe(recipe models.Recipe) models.Recipe {
+	recipe.Ingredients = s.ingredientsByRecipeID(recipe.ID)
+	recipe.Categories = s.categoriesByRecipeID(recipe.ID)
+
+	return recipe
+}
+
+// matchRecipe reports whether a recipe passes filter. The caller must hold the lock.
+func (s *Store) matchRecipe(recipe models.Recipe, filter models.RecipeFilter) bool {
+	if filter.Difficulty != "" && recipe.Difficulty != filter.Difficulty {
+		return false
+	}
+	if filter.MaxPrepTime > 0 && recipe.PrepTime > filter.MaxPrepTime {
+		return false
+	}
+	if filter.CategoryID > 0 && !slices.Contains(s.recipeCategories[recipe.ID], filter.CategoryID) {
+		return false
+	}
+	if filter.IngredientID > 0 && !slices.ContainsFunc(s.recipeIngredients[recipe.ID], func(line models.RecipeIngredient) bool {
+		return line.IngredientID == filter.IngredientID
+	}) {
+		return false
+	}
+	if filter.AuthorID > 0 && recipe.AuthorID != filter.AuthorID {
+		return false
+	}
+
+	return true
+}
+
+// recipeLess returns the ordering of recipes for a sort key.
+func recipeLess(key string) func(a, b models.Recipe) bool {
+	switch key {
+	case models.SortByTitle:
+		return func(a, b models.Recipe) bool { return a.Title < b.Title }
+	case models.SortByPrepTime:
+		return func(a, b models.Recipe) bool { return a.PrepTime < b.PrepTime }
+	case models.SortByCreatedAt:
+		return func(a, b models.Recipe) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	default:
+		return func(a, b models.Recipe) bool { return false }
+	}
+}
+
+// checkRecipe reports an error if links are being added to a recipe that does
+// not exist. The caller must hold the lock.
+func (s *Store) checkRecipe(recipeID int, links int) error {
+	if _, ok := s.recipes[recipeID]; !ok && links > 0 {
+		return missingReference("recipe", recipeID)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/logging"
+	"github.com/keevferreira/recipes-api/internal/models"
+	"github.com/keevferreira/recipes-api/internal/search"
+)
+
+// snippetWords is the length of the snippets returned by SearchRecipes.
+const snippetWords = 16
+
+// searchField is a text of a recipe and its weight in the ranking.
+type searchField struct {
+	text   string
+	weight float64
+}
+
+// SearchRecipes scans every recipe of the store for the query terms. A recipe
+// matches when each term is found in one of its fields and no excluded term
+// is; its rank adds up the matches weighted by field, following the weights
+// of the PostgreSQL backend.
+func (rr *RecipeRepository) SearchRecipes(ctx context.Context, query string, options models.ListOptions) ([]models.RecipeSearchResult, models.PageInfo, error) {
+	parsed := search.Parse(query)
+	terms := parsed.Terms
+	if len(terms) == 0 {
+		return nil, models.PageInfo{}, nil
+	}
+
+	rr.store.mu.RLock()
+	defer rr.store.mu.RUnlock()
+
+	var results []models.RecipeSearchResult
+	for id, recipe := range rr.store.recipes {
+		var names, steps []string
+		for _, line := range rr.store.ingredientsByRecipeID(id) {
+			names = append(names, line.Name)
+		}
+		for _, step := range rr.store.recipeSteps[id] {
+			steps = append(steps, step.Text)
+		}
+
+		fields := []searchField{
+			{recipe.Title, 1.0},
+			{strings.Join(names, " "), 0.4},
+			{recipe.Description, 0.2},
+			{strings.Join(steps, " "), 0.1},
+		}
+
+		rank, ok := rankFields(fields, terms)
+		if !ok || excludes(fields, parsed.Excluded) {
+			continue
+		}
+
+		results = append(results, models.RecipeSearchResult{
+			RecipeID: id,
+			Title:    recipe.Title,
+			Rank:     rank,
+			Snippet:  search.Snippet(snippetText([]searchField{fields[2], fields[3], fields[1]}, terms), terms, snippetWords),
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].RecipeID < results[j].RecipeID
+	})
+
+	total := len(results)
+	start := min(options.Offset, total)
+	end := min(start+options.Limit+1, total)
+	page, info := models.Page(options, results[start:end], total)
+	logging.FromContext(ctx).Debug("recipe search", slog.String("query", query), slog.Int("total", total))
+
+	return page, info, nil
+}
+
+// rankFields returns the weighted number of matches in fields, and false if
+// a term is not found in any of them.
+func rankFields(fields []searchField, terms []string) (float64, bool) {
+	var rank float64
+	for _, term := range terms {
+		found := false
+		for _, field := range fields {
+			if count := search.Count(field.text, []string{term}); count > 0 {
+				rank += field.weight * float64(count)
+				found = true
+			}
+		}
+
+		if !found {
+			return 0, false
+		}
+	}
+
+	return rank, true
+}
+
+// excludes reports whether any of the excluded terms is found in fields.
+func excludes(fields []searchField, excluded []string) bool {
+	for _, field := range fields {
+		if search.Count(field.text, excluded) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// snippetText picks the first of fields with a match to excerpt, or the
+// first field when the terms are only in the title.
+func snippetText(fields []searchField, terms []string) string {
+	for _, field := range fields {
+		if search.Count(field.text, terms) > 0 {
+			return field.text
+		}
+	}
+
+	return fields[0].text
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// ShoppingListRepository is the in-memory implementation of models.ShoppingListRepository.
+type ShoppingListRepository struct {
+	store *Store
+}
+
+// NewShoppingListRepository creates a ShoppingListRepository backed by store.
+func NewShoppingListRepository(store *Store) *ShoppingListRepository {
+	return &ShoppingListRepository{store: store}
+}
+
+// GetShoppingListByID retrieves a shopping list with its items from the store.
+func (sr *ShoppingListRepository) GetShoppingListByID(ctx context.Context, id int) (models.ShoppingList, error) {
+	sr.store.mu.RLock()
+	defer sr.store.mu.RUnlock()
+
+	list, ok := sr.store.shoppingLists[id]
+	if !ok {
+		return models.ShoppingList{}, models.NewNotFoundError("shopping list", id)
+	}
+
+	items := list.Items
+	list.Items = nil
+	for _, item := range items {
+		item.Name = sr.store.ingredients[item.IngredientID].Name
+		list.Items = append(list.Items, item)
+	}
+
+	return list, nil
+}
+
+// CreateShoppingList creates a shopping list with its items in the store.
+func (sr *ShoppingListRepository) CreateShoppingList(ctx context.Context, list models.ShoppingList) (int, error) {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	items := make([]models.ShoppingListItem, 0, len(list.Items))
+	for _, item := range list.Items {
+		if _, ok := sr.store.ingredients[item.IngredientID]; !ok {
+			return 0, missingReference("ingredient", item.IngredientID)
+		}
+
+		sr.store.nextListItemID++
+		item.ID = sr.store.nextListItemID
+		item.Name = ""
+		items = append(items, item)
+	}
+
+	sr.store.nextListID++
+	list.ID = sr.store.nextListID
+	list.Items = items
+	list.CreatedAt = time.Now()
+	list.UpdatedAt = list.CreatedAt
+	sr.store.shoppingLists[list.ID] = list
+
+	return list.ID, nil
+}
+
+// DeleteShoppingListByID deletes a shopping list and its items from the store.
+func (sr *ShoppingListRepository) DeleteShoppingListByID(ctx context.Context, id int) error {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	if _, ok := sr.store.shoppingLists[id]; !ok {
+		return models.NewNotFoundError("shopping list", id)
+	}
+
+	delete(sr.store.shoppingLists, id)
+
+	return nil
+}
+
+// CheckShoppingListItem marks an item of a shopping list as bought or not.
+func (sr *ShoppingListRepository) CheckShoppingListItem(ctx context.Context, listID int, itemID int, checked bool) error {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	list, ok := sr.store.shoppingLists[listID]
+	if ok {
+		for i := range list.Items {
+			if list.Items[i].ID == itemID {
+				list.Items[i].Checked = checked
+				return nil
+			}
+		}
+	}
+
+	return models.NewNotFoundError("shopping list item", itemID)
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// GetStepsByRecipeID retrieves the steps of a recipe ordered by position.
+func (rr *RecipeRepository) GetStepsByRecipeID(ctx context.Context, recipeID int) ([]models.Step, error) {
+	rr.store.mu.RLock()
+	defer rr.store.mu.RUnlock()
+
+	return rr.store.stepsByRecipeID(recipeID), nil
+}
+
+// ReorderStepsByRecipeID rewrites the position of every step of a recipe to follow stepIDs.
+func (rr *RecipeRepository) ReorderStepsByRecipeID(ctx context.Context, recipeID int, stepIDs []int) error {
+	rr.store.mu.Lock()
+	defer rr.store.mu.Unlock()
+
+	if _, ok := rr.store.recipes[recipeID]; !ok {
+		return models.NewNotFoundError("recipe", recipeID)
+	}
+
+	current := rr.store.recipeSteps[recipeID]
+	if len(stepIDs) != len(current) {
+		return models.NewFieldError("step_ids", models.FieldInvalid, "recipe with ID %d has %d steps, got %d in the new order", recipeID, len(current), len(stepIDs))
+	}
+
+	byID := make(map[int]models.Step, len(current))
+	for _, step := range current {
+		byID[step.ID] = step
+	}
+
+	seen := make(map[int]bool, len(stepIDs))
+	reordered := make([]models.Step, 0, len(stepIDs))
+	for i, stepID := range stepIDs {
+		step, ok := byID[stepID]
+		if !ok {
+			return models.NewFieldError("step_ids", models.FieldInvalid, "step with ID %d does not belong to recipe with ID %d", stepID, recipeID)
+		}
+		if seen[stepID] {
+			return models.NewFieldError("step_ids", models.FieldInvalid, "step with ID %d is listed more than once", stepID)
+		}
+
+		seen[stepID] = true
+		step.Position = i + 1
+		reordered = append(reordered, step)
+	}
+
+	if len(reordered) > 0 {
+		rr.store.recipeSteps[recipeID] = reordered
+	}
+
+	return nil
+}
+
+// stepsByRecipeID returns a copy of the steps of a recipe. The caller must hold the lock.
+func (s *Store) stepsByRecipeID(recipeID int) []models.Step {
+	var steps []models.Step
+	for _, step := range s.recipeSteps[recipeID] {
+		step.IngredientIDs = append([]int(nil), step.IngredientIDs...)
+		steps = append(steps, step)
+	}
+
+	return steps
+}
+
+// checkSteps reports an error if a step uses an ingredient that does not exist. The caller must hold the lock.
+func (s *Store) checkSteps(steps []models.Step) error {
+	for _, step := range steps {
+		for _, ingredientID := range step.IngredientIDs {
+			if _, ok := s.ingredients[ingredientID]; !ok {
+				return missingReference("ingredient", ingredientID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkStepIDs reports an error if a step has the ID of a step that is not one of the
+// current steps of the recipe, or if two steps have the same ID. Steps without an ID
+// are new. The caller must hold the lock.
+func (s *Store) checkStepIDs(recipeID int, steps []models.Step) error {
+	current := make(map[int]bool)
+	for _, step := range s.recipeSteps[recipeID] {
+		current[step.ID] = true
+	}
+
+	seen := make(map[int]bool, len(steps))
+	for i, step := range steps {
+		if step.ID == 0 {
+			continue
+		}
+
+		field := fmt.Sprintf("steps[%d].id", i)
+		if !current[step.ID] {
+			return models.NewFieldError(field, models.FieldInvalid, "step with ID %d does not belong to recipe with ID %d", step.ID, recipeID)
+		}
+		if seen[step.ID] {
+			return models.NewFieldError(field, models.FieldInvalid, "step with ID %d is listed more than once", step.ID)
+		}
+		seen[step.ID] = true
+	}
+
+	return nil
+}
+
+// setStepsByRecipeID replaces the steps of a recipe, positioned in slice order. Steps
+// with the ID of a current step keep it; the others get a new one. The caller must
+// hold the lock.
+func (s *Store) setStepsByRecipeID(recipeID int, steps []models.Step) {
+	if len(steps) == 0 {
+		delete(s.recipeSteps, recipeID)
+		return
+	}
+
+	current := make(map[int]bool)
+	for _, step := range s.recipeSteps[recipeID] {
+		current[step.ID] = true
+	}
+
+	stored := make([]models.Step, 0, len(steps))
+	for i, step := range steps {
+		if !current[step.ID] {
+			s.nextStepID++
+			step.ID = s.nextStepID
+		}
+		step.Position = i + 1
+		step.IngredientIDs = append([]int(nil), step.IngredientIDs...)
+		stored = append(stored, step)
+	}
+	s.recipeSteps[recipeID] = stored
+}
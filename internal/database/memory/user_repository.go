@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// UserRepository is the in-memory implementation of models.UserRepository.
+type UserRepository struct {
+	store *Store
+}
+
+// NewUserRepository creates a UserRepository backed by store.
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+// GetUserByID retrieves a user by its ID from the store.
+func (ur *UserRepository) GetUserByID(ctx context.Context, id int) (models.User, error) {
+	ur.store.mu.RLock()
+	defer ur.store.mu.RUnlock()
+
+	user, ok := ur.store.users[id]
+	if !ok {
+		return models.User{}, models.NewNotFoundError("user", id)
+	}
+
+	return user, nil
+}
+
+// GetUserByEmail retrieves a user by its e-mail from the store.
+func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	ur.store.mu.RLock()
+	defer ur.store.mu.RUnlock()
+
+	for _, user := range ur.store.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return models.User{}, fmt.Errorf("user %w", models.ErrNotFound)
+}
+
+// CreateUser creates a new user in the store.
+func (ur *UserRepository) CreateUser(ctx context.Context, user models.User) (int, error) {
+	ur.store.mu.Lock()
+	defer ur.store.mu.Unlock()
+
+	for _, existing := range ur.store.users {
+		if existing.Email == user.Email {
+			return 0, models.NewConflictError("a user with e-mail %s already exists", user.Email)
+		}
+	}
+
+	ur.store.nextUserID++
+	user.ID = ur.store.nextUserID
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	ur.store.users[user.ID] = user
+
+	return user.ID, nil
+}
+
+// SetUserRole changes the role of a user in the store.
+func (ur *UserRepository) SetUserRole(ctx context.Context, id int, role string) error {
+	ur.store.mu.Lock()
+	defer ur.store.mu.Unlock()
+
+	user, ok := ur.store.users[id]
+	if !ok {
+		return models.NewNotFoundError("user", id)
+	}
+
+	user.Role = role
+	user.UpdatedAt = time.Now()
+	ur.store.users[id] = user
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package migrate
+
+// Dialect holds what differs between the databases the Migrator runs on.
+// Postgres and SQLite are its implementations.
+type Dialect interface {
+	// tableExists returns a read-only query telling whether the table named
+	// by its only argument exists.
+	tableExists() string
+	// lock returns the statement taking the lock that keeps two Migrators
+	// from changing the schema at the same time, and unlock the one releasing
+	// it. Both take the key of the lock as their only argument and run on the
+	// same connection. An empty lock means the database needs none.
+	lock() string
+	unlock() string
+}
+
+// Postgres is the Dialect of PostgreSQL.
+var Postgres Dialect = postgresDialect{}
+
+type postgresDialect struct{}
+
+func (postgresDialect) tableExists() string {
+	return "SELECT to_regclass($1) IS NOT NULL"
+}
+
+// lock takes a session advisory lock, which waits for the replicas that
+// started first to finish their migrations.
+func (postgresDialect) lock() string {
+	return "SELECT pg_advisory_lock($1)"
+}
+
+func (postgresDialect) unlock() string {
+	return "SELECT pg_advisory_unlock($1)"
+}
+
+// SQLite is the Dialect of SQLite.
+var SQLite Dialect = sqliteDialect{}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) tableExists() string {
+	return "SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = $1"
+}
+
+// lock is empty, as SQLite already serializes the transactions that write.
+func (sqliteDialect) lock() string {
+	return ""
+}
+
+func (sqliteDialect) unlock() string {
+	return ""
+}
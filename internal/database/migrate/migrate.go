@@ -0,0 +1,317 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const createSchemaMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)
+`
+
+// lockKey is the key of the lock held while migrations are applied or
+// reverted. It only has to differ from the other advisory locks taken on the
+// database.
+const lockKey = 7265636970657300
+
+// queryer is what the Migrator reads and writes through: the pool, or the
+// connection that holds the lock.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Migration is a versioned schema change read from a pair of
+// NNN_name.up.sql and NNN_name.down.sql files.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied to the database.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies and reverts migrations, recording the applied versions in
+// the schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New creates a Migrator for db, which speaks dialect, with the migrations
+// found at the root of files.
+func New(db *sql.DB, dialect Dialect, files fs.FS) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Latest returns the version of the newest known migration.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the newest applied version, or 0 if none has been applied.
+// It only reads from the database, so that health checks may call it.
+func (m *Migrator) Version() (int, error) {
+	applied, err := applied(context.Background(), m.db, m.dialect)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+
+	return version, nil
+}
+
+// Up applies every pending migration in version order and returns the ones
+// applied. Replicas starting together wait for each other, so each
+// migration is applied once.
+func (m *Migrator) Up() ([]Migration, error) {
+	var done []Migration
+	err := m.locked(func(ctx context.Context, q queryer) error {
+		applied, err := applied(ctx, q, m.dialect)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err := run(ctx, q, migration.Up, "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)",
+				migration.Version, migration.Name, time.Now())
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s up: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the n most recently applied migrations and returns the ones reverted.
+func (m *Migrator) Down(n int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(func(ctx context.Context, q queryer) error {
+		applied, err := applied(ctx, q, m.dialect)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < n; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			err := run(ctx, q, migration.Down, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s down: %w", migration.Version, migration.Name, err)
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// locked runs fn on a connection holding the lock of the dialect, once the
+// schema_migrations table exists. The applied versions must be read inside
+// fn, as another Migrator may have changed them while this one waited.
+func (m *Migrator) locked(fn func(ctx context.Context, q queryer) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if lock := m.dialect.lock(); lock != "" {
+		if _, err := conn.ExecContext(ctx, lock, lockKey); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, m.dialect.unlock(), lockKey)
+	}
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrationsTable); err != nil {
+		return err
+	}
+
+	return fn(ctx, conn)
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := applied(context.Background(), m.db, m.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: appliedAt})
+	}
+
+	return statuses, nil
+}
+
+// applied returns the applied versions with the time they were applied, none
+// when the schema_migrations table was not created yet.
+func applied(ctx context.Context, q queryer, dialect Dialect) (map[int]time.Time, error) {
+	applied := make(map[int]time.Time)
+
+	var exists bool
+	if err := q.QueryRowContext(ctx, dialect.tableExists(), "schema_migrations").Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// run executes a migration script and the matching schema_migrations change in one transaction.
+func run(ctx context.Context, q queryer, script string, record string, args ...any) error {
+	tx, err := q.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// load reads the migration files from the root of files, requiring every
+// version to have both an up and a down script.
+func load(files fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, fileName := range names {
+		base, direction, ok := cutDirection(fileName)
+		if !ok {
+			return nil, fmt.Errorf("migration %s: name must end in .up.sql or .down.sql", fileName)
+		}
+
+		prefix, name, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: name must start with a version number", fileName)
+		}
+
+		script, err := fs.ReadFile(files, fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s must have both up and down scripts", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// cutDirection splits "001_name.up.sql" into "001_name" and "up".
+func cutDirection(fileName string) (base string, direction string, ok bool) {
+	base = strings.TrimSuffix(path.Base(fileName), ".sql")
+	for _, direction := range []string{"up", "down"} {
+		if trimmed, found := strings.CutSuffix(base, "."+direction); found {
+			return trimmed, direction, true
+		}
+	}
+	return "", "", false
+}
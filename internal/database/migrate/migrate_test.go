@@ -0,0 +1,166 @@
+package migrate_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/keevferreira/recipes-api/internal/database/migrate"
+	"github.com/keevferreira/recipes-api/internal/database/sqlstore"
+)
+
+func TestMigrator(t *testing.T) {
+	db, err := sqlstore.ConnectToSQLiteDB(filepath.Join(t.TempDir(), "recipes.db"))
+	if err != nil {
+		t.Fatalf("ConnectToSQLiteDB: %v", err)
+	}
+	defer db.Close()
+
+	files := fstest.MapFS{
+		"001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER)")},
+		"001_create_a.down.sql": {Data: []byte("DROP TABLE a")},
+		"002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER)")},
+		"002_create_b.down.sql": {Data: []byte("DROP TABLE b")},
+	}
+	migrator, err := migrate.New(db, migrate.SQLite, files)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// version runs Version and returns its result.
+	version := func() int {
+		t.Helper()
+		v, err := migrator.Version()
+		if err != nil {
+			t.Fatalf("Version: %v", err)
+		}
+		return v
+	}
+
+	if v := version(); v != 0 {
+		t.Errorf("Version of an empty database = %d, want 0", v)
+	}
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_migrations'").Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Errorf("Version created the schema_migrations table")
+	}
+
+	applied, err := migrator.Up()
+	if err != nil || len(applied) != 2 {
+		t.Fatalf("Up = %v, %v, want both migrations", applied, err)
+	}
+	if v := version(); v != 2 {
+		t.Errorf("Version after Up = %d, want 2", v)
+	}
+
+	applied, err = migrator.Up()
+	if err != nil || len(applied) != 0 {
+		t.Errorf("second Up = %v, %v, want none", applied, err)
+	}
+
+	reverted, err := migrator.Down(1)
+	if err != nil || len(reverted) != 1 || reverted[0].Version != 2 {
+		t.Fatalf("Down(1) = %v, %v, want the version 2", reverted, err)
+	}
+	if v := version(); v != 1 {
+		t.Errorf("Version after Down = %d, want 1", v)
+	}
+}
+
+func TestMigratorFailure(t *testing.T) {
+	db, err := sqlstore.ConnectToSQLiteDB(filepath.Join(t.TempDir(), "recipes.db"))
+	if err != nil {
+		t.Fatalf("ConnectToSQLiteDB: %v", err)
+	}
+	defer db.Close()
+
+	// The second statement fails, so the table of the first must not stay
+	files := fstest.MapFS{
+		"001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER)")},
+		"001_create_a.down.sql": {Data: []byte("DROP TABLE a")},
+		"002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER); INSERT INTO missing VALUES (1)")},
+		"002_create_b.down.sql": {Data: []byte("DROP TABLE b")},
+	}
+	migrator, err := migrate.New(db, migrate.SQLite, files)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	applied, err := migrator.Up()
+	if err == nil || len(applied) != 1 {
+		t.Fatalf("Up = %v, %v, want the version 1 and an error", applied, err)
+	}
+	if v, err := migrator.Version(); err != nil || v != 1 {
+		t.Errorf("Version = %d, %v, want 1", v, err)
+	}
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'b'").Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Errorf("the failed migration left its table behind")
+	}
+}
+
+// TestPostgresConcurrentUp runs against the database at DATABASE_URL and is
+// skipped when the variable is unset.
+func TestPostgresConcurrentUp(t *testing.T) {
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	db, err := sqlstore.ConnectToPostgresDB(url)
+	if err != nil {
+		t.Fatalf("ConnectToPostgresDB: %v", err)
+	}
+	defer sqlstore.DisconnectPostgresDB(db)
+
+	// The version is far past the real migrations, and the script fails if
+	// it runs twice
+	files := fstest.MapFS{
+		"9001_create_migrate_lock_test.up.sql":   {Data: []byte("CREATE TABLE migrate_lock_test (id INT)")},
+		"9001_create_migrate_lock_test.down.sql": {Data: []byte("DROP TABLE migrate_lock_test")},
+	}
+
+	const replicas = 4
+	var wg sync.WaitGroup
+	applied := make([]int, replicas)
+	errs := make([]error, replicas)
+	for i := 0; i < replicas; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			migrator, err := migrate.New(db, migrate.Postgres, files)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			done, err := migrator.Up()
+			applied[i], errs[i] = len(done), err
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for i := range errs {
+		if errs[i] != nil {
+			t.Errorf("Up of replica %d: %v", i, errs[i])
+		}
+		total += applied[i]
+	}
+	if total != 1 {
+		t.Errorf("the replicas applied %d migrations, want 1", total)
+	}
+
+	migrator, _ := migrate.New(db, migrate.Postgres, files)
+	if _, err := migrator.Down(1); err != nil {
+		t.Errorf("Down: %v", err)
+	}
+}
@@ -1,4 +1,4 @@
-CREATE TABLE Recipe (
+CREATE TABLE IF NOT EXISTS Recipe (
     ID SERIAL PRIMARY KEY,
     Title VARCHAR(255) NOT NULL,
     Description TEXT NOT NULL,
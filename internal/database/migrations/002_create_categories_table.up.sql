@@ -1,4 +1,4 @@
-CREATE TABLE Category (
+CREATE TABLE IF NOT EXISTS Category (
     ID SERIAL PRIMARY KEY,
     Name VARCHAR(255) NOT NULL,
     Description TEXT,
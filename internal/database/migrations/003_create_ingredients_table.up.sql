@@ -1,4 +1,4 @@
-CREATE TABLE Ingredient (
+CREATE TABLE IF NOT EXISTS Ingredient (
     ID SERIAL PRIMARY KEY,
     Name VARCHAR(255) NOT NULL,
     CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -1,4 +1,4 @@
-CREATE TABLE RecipeIngredients (
+CREATE TABLE IF NOT EXISTS RecipeIngredients (
     ID SERIAL PRIMARY KEY,
     RecipeID INT NOT NULL,
     IngredientID INT NOT NULL,
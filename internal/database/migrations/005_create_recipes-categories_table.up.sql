@@ -1,4 +1,4 @@
-CREATE TABLE RecipeCategories (
+CREATE TABLE IF NOT EXISTS RecipeCategories (
     ID SERIAL PRIMARY KEY,
     RecipeID INT NOT NULL,
     CategoryID INT NOT NULL,
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS RecipeSteps (
+    ID SERIAL PRIMARY KEY,
+    RecipeID INT NOT NULL,
+    Position INT NOT NULL,
+    Text TEXT NOT NULL,
+    Duration INT,
+    Temperature FLOAT,
+    TemperatureUnit VARCHAR(10),
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (RecipeID) REFERENCES Recipe(ID)
+);
+
+CREATE TABLE IF NOT EXISTS RecipeStepIngredients (
+    ID SERIAL PRIMARY KEY,
+    StepID INT NOT NULL,
+    IngredientID INT NOT NULL,
+    FOREIGN KEY (StepID) REFERENCES RecipeSteps(ID) ON DELETE CASCADE,
+    FOREIGN KEY (IngredientID) REFERENCES Ingredient(ID)
+);
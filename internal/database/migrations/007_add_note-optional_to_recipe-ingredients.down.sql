@@ -0,0 +1,3 @@
+ALTER TABLE RecipeIngredients
+    DROP COLUMN IF EXISTS Note,
+    DROP COLUMN IF EXISTS Optional;
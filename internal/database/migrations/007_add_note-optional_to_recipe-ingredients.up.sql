@@ -0,0 +1,3 @@
+ALTER TABLE RecipeIngredients
+    ADD COLUMN IF NOT EXISTS Note TEXT,
+    ADD COLUMN IF NOT EXISTS Optional BOOLEAN NOT NULL DEFAULT FALSE;
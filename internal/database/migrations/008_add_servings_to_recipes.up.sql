@@ -0,0 +1 @@
+ALTER TABLE Recipe ADD COLUMN IF NOT EXISTS Servings INT;
@@ -0,0 +1 @@
+ALTER TABLE Ingredient ADD COLUMN IF NOT EXISTS Aisle VARCHAR(100);
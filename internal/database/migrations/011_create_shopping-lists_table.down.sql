@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ShoppingListItems;
+
+DROP TABLE IF EXISTS ShoppingLists;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS PantryItems (
+    ID SERIAL PRIMARY KEY,
+    IngredientID INT NOT NULL UNIQUE,
+    Quantity FLOAT,
+    Unit VARCHAR(50),
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (IngredientID) REFERENCES Ingredient(ID)
+);
+
+CREATE INDEX IF NOT EXISTS recipeingredients_recipeid_idx ON RecipeIngredients (RecipeID);
+
+CREATE INDEX IF NOT EXISTS recipeingredients_ingredientid_idx ON RecipeIngredients (IngredientID);
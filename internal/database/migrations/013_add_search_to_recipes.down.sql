@@ -0,0 +1,16 @@
+DROP TRIGGER IF EXISTS ingredient_search_vector_refresh ON Ingredient;
+DROP TRIGGER IF EXISTS recipesteps_search_vector_refresh ON RecipeSteps;
+DROP TRIGGER IF EXISTS recipeingredients_search_vector_refresh ON RecipeIngredients;
+DROP TRIGGER IF EXISTS recipe_search_vector_update ON Recipe;
+
+DROP FUNCTION IF EXISTS ingredient_search_vector_refresh();
+DROP FUNCTION IF EXISTS recipe_search_vector_refresh();
+DROP FUNCTION IF EXISTS recipe_search_vector_update();
+DROP FUNCTION IF EXISTS recipe_search_vector(INT, TEXT, TEXT);
+
+DROP INDEX IF EXISTS recipe_searchvector_idx;
+ALTER TABLE Recipe DROP COLUMN IF EXISTS SearchVector;
+
+-- The unaccent extension stays: other objects of the database may use it, and
+-- CREATE EXTENSION IF NOT EXISTS keeps the up script rerunnable
+DROP TEXT SEARCH CONFIGURATION IF EXISTS portuguese_unaccent;
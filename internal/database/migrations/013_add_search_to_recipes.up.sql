@@ -0,0 +1,77 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Portuguese stemming on accent-free words, so "pão" and "pães" match "pao"
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'portuguese_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION portuguese_unaccent (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION portuguese_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+ALTER TABLE Recipe ADD COLUMN IF NOT EXISTS SearchVector TSVECTOR;
+
+CREATE INDEX IF NOT EXISTS recipe_searchvector_idx ON Recipe USING GIN (SearchVector);
+
+-- The title weighs the most, then the ingredient names, the description and the steps
+CREATE OR REPLACE FUNCTION recipe_search_vector(p_recipe_id INT, p_title TEXT, p_description TEXT) RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('portuguese_unaccent', COALESCE(p_title, '')), 'A')
+        || setweight(to_tsvector('portuguese_unaccent', COALESCE((
+            SELECT string_agg(i.Name, ' ')
+            FROM RecipeIngredients ri
+            INNER JOIN Ingredient i ON i.ID = ri.IngredientID
+            WHERE ri.RecipeID = p_recipe_id), '')), 'B')
+        || setweight(to_tsvector('portuguese_unaccent', COALESCE(p_description, '')), 'C')
+        || setweight(to_tsvector('portuguese_unaccent', COALESCE((
+            SELECT string_agg(s.Text, ' ')
+            FROM RecipeSteps s
+            WHERE s.RecipeID = p_recipe_id), '')), 'D')
+$$ LANGUAGE SQL STABLE;
+
+CREATE OR REPLACE FUNCTION recipe_search_vector_update() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.SearchVector := recipe_search_vector(NEW.ID, NEW.Title, NEW.Description);
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS recipe_search_vector_update ON Recipe;
+CREATE TRIGGER recipe_search_vector_update BEFORE INSERT OR UPDATE OF Title, Description ON Recipe
+    FOR EACH ROW EXECUTE FUNCTION recipe_search_vector_update();
+
+-- Ingredient lines and steps are written after their recipe, so they refresh its vector
+CREATE OR REPLACE FUNCTION recipe_search_vector_refresh() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        UPDATE Recipe SET SearchVector = recipe_search_vector(ID, Title, Description) WHERE ID = OLD.RecipeID;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        UPDATE Recipe SET SearchVector = recipe_search_vector(ID, Title, Description) WHERE ID = NEW.RecipeID;
+    END IF;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS recipeingredients_search_vector_refresh ON RecipeIngredients;
+CREATE TRIGGER recipeingredients_search_vector_refresh AFTER INSERT OR UPDATE OR DELETE ON RecipeIngredients
+    FOR EACH ROW EXECUTE FUNCTION recipe_search_vector_refresh();
+
+DROP TRIGGER IF EXISTS recipesteps_search_vector_refresh ON RecipeSteps;
+CREATE TRIGGER recipesteps_search_vector_refresh AFTER INSERT OR UPDATE OR DELETE ON RecipeSteps
+    FOR EACH ROW EXECUTE FUNCTION recipe_search_vector_refresh();
+
+CREATE OR REPLACE FUNCTION ingredient_search_vector_refresh() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE Recipe SET SearchVector = recipe_search_vector(ID, Title, Description)
+    WHERE ID IN (SELECT RecipeID FROM RecipeIngredients WHERE IngredientID = NEW.ID);
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ingredient_search_vector_refresh ON Ingredient;
+CREATE TRIGGER ingredient_search_vector_refresh AFTER UPDATE OF Name ON Ingredient
+    FOR EACH ROW EXECUTE FUNCTION ingredient_search_vector_refresh();
+
+UPDATE Recipe SET SearchVector = recipe_search_vector(ID, Title, Description);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS ApiKeys (
+    ID SERIAL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL,
+    KeyHash CHAR(64) NOT NULL UNIQUE,
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    RevokedAt TIMESTAMP
+);
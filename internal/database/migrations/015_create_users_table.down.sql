@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS recipe_authorid_idx;
+ALTER TABLE Recipe DROP COLUMN IF EXISTS AuthorID;
+
+DROP TABLE IF EXISTS Users;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS Users (
+    ID SERIAL PRIMARY KEY,
+    Email VARCHAR(255) NOT NULL UNIQUE,
+    Name VARCHAR(255) NOT NULL,
+    PasswordHash VARCHAR(255) NOT NULL,
+    Role VARCHAR(20) NOT NULL DEFAULT 'author',
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Recipes created before the users existed have no author
+ALTER TABLE Recipe ADD COLUMN IF NOT EXISTS AuthorID INT REFERENCES Users(ID) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS recipe_authorid_idx ON Recipe (AuthorID);
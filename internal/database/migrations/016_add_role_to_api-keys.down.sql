@@ -0,0 +1 @@
+ALTER TABLE ApiKeys DROP COLUMN IF EXISTS Role;
@@ -0,0 +1,7 @@
+-- New keys get the least privileged role unless told otherwise. The keys
+-- created before the column existed could read and write recipes, catalogs
+-- and plans, which is what author allows; raise a key that needs more with
+-- "recipes-api apikey role ID PAPEL"
+ALTER TABLE ApiKeys ADD COLUMN IF NOT EXISTS Role VARCHAR(20) NOT NULL DEFAULT 'viewer';
+
+UPDATE ApiKeys SET Role = 'author';
@@ -0,0 +1 @@
+ALTER TABLE ShoppingLists DROP COLUMN IF EXISTS Owner;
@@ -0,0 +1,4 @@
+-- The owner is the subject of the principal that created the list, such as
+-- "user:1" or "api_key:2". Lists created before owners existed have none and
+-- are only reachable with the planning:manage permission
+ALTER TABLE ShoppingLists ADD COLUMN IF NOT EXISTS Owner VARCHAR(255);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS pantryitems_owner_ingredientid_idx;
+
+-- A single pantry holds each ingredient once, so only the oldest item of
+-- each ingredient is kept
+DELETE FROM PantryItems p
+WHERE EXISTS (SELECT 1 FROM PantryItems o WHERE o.IngredientID = p.IngredientID AND o.ID < p.ID);
+
+ALTER TABLE PantryItems ADD CONSTRAINT pantryitems_ingredientid_key UNIQUE (IngredientID);
+
+ALTER TABLE PantryItems DROP COLUMN IF EXISTS Owner;
@@ -0,0 +1,8 @@
+-- Each principal keeps its own pantry, identified by its subject, such as
+-- "user:1" or "api_key:2". The items stored before pantries had owners are
+-- left with an empty owner, which no principal has
+ALTER TABLE PantryItems ADD COLUMN IF NOT EXISTS Owner VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE PantryItems DROP CONSTRAINT IF EXISTS pantryitems_ingredientid_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS pantryitems_owner_ingredientid_idx ON PantryItems (Owner, IngredientID);
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Files contém os scripts de migração do PostgreSQL embutidos no binário.
+// Cada versão tem um script NNN_nome.up.sql e o seu NNN_nome.down.sql.
+//
+//go:embed *.sql
+var Files embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLite retorna os scripts equivalentes para o SQLite, com as mesmas versões.
+func SQLite() fs.FS {
+	files, _ := fs.Sub(sqliteFiles, "sqlite")
+	return files
+}
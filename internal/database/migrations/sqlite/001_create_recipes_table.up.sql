@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS Recipe (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Title VARCHAR(255) NOT NULL,
+    Description TEXT NOT NULL,
+    PrepTime INT NOT NULL,
+    Difficulty VARCHAR(50) NOT NULL,
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS RecipeIngredients (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    RecipeID INT NOT NULL,
+    IngredientID INT NOT NULL,
+    Quantity FLOAT,
+    Unit VARCHAR(50),
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (RecipeID) REFERENCES Recipe(ID),
+    FOREIGN KEY (IngredientID) REFERENCES Ingredient(ID)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS RecipeCategories (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    RecipeID INT NOT NULL,
+    CategoryID INT NOT NULL,
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (RecipeID) REFERENCES Recipe(ID),
+    FOREIGN KEY (CategoryID) REFERENCES Category(ID)
+);
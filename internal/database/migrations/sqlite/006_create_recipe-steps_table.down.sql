@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS RecipeStepIngredients;
+
+DROP TABLE IF EXISTS RecipeSteps;
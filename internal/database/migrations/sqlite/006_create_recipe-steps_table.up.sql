@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS RecipeSteps (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    RecipeID INT NOT NULL,
+    Position INT NOT NULL,
+    Text TEXT NOT NULL,
+    Duration INT,
+    Temperature FLOAT,
+    TemperatureUnit VARCHAR(10),
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (RecipeID) REFERENCES Recipe(ID)
+);
+
+CREATE TABLE IF NOT EXISTS RecipeStepIngredients (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    StepID INT NOT NULL,
+    IngredientID INT NOT NULL,
+    FOREIGN KEY (StepID) REFERENCES RecipeSteps(ID) ON DELETE CASCADE,
+    FOREIGN KEY (IngredientID) REFERENCES Ingredient(ID)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE RecipeIngredients DROP COLUMN Optional;
+
+ALTER TABLE RecipeIngredients DROP COLUMN Note;
@@ -0,0 +1,3 @@
+ALTER TABLE RecipeIngredients ADD COLUMN Note TEXT;
+
+ALTER TABLE RecipeIngredients ADD COLUMN Optional BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1 @@
+ALTER TABLE Recipe DROP COLUMN Servings;
@@ -0,0 +1 @@
+ALTER TABLE Recipe ADD COLUMN Servings INT;
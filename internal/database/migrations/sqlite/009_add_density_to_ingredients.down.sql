@@ -0,0 +1 @@
+ALTER TABLE Ingredient DROP COLUMN Density;
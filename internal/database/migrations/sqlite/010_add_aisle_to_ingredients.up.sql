@@ -0,0 +1 @@
+ALTER TABLE Ingredient ADD COLUMN Aisle VARCHAR(100);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS ShoppingLists (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Name VARCHAR(255) NOT NULL,
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS ShoppingListItems (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    ShoppingListID INT NOT NULL,
+    IngredientID INT NOT NULL,
+    Aisle VARCHAR(100),
+    Quantity FLOAT NOT NULL,
+    Unit VARCHAR(50),
+    Checked BOOLEAN NOT NULL DEFAULT FALSE,
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (ShoppingListID) REFERENCES ShoppingLists(ID) ON DELETE CASCADE,
+    FOREIGN KEY (IngredientID) REFERENCES Ingredient(ID)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS recipeingredients_ingredientid_idx;
+
+DROP INDEX IF EXISTS recipeingredients_recipeid_idx;
+
+DROP TABLE IF EXISTS PantryItems;
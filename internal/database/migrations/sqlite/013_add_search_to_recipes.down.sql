@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS ingredient_search_update;
+DROP TRIGGER IF EXISTS recipesteps_search_delete;
+DROP TRIGGER IF EXISTS recipesteps_search_update;
+DROP TRIGGER IF EXISTS recipesteps_search_insert;
+DROP TRIGGER IF EXISTS recipeingredients_search_delete;
+DROP TRIGGER IF EXISTS recipeingredients_search_update;
+DROP TRIGGER IF EXISTS recipeingredients_search_insert;
+DROP TRIGGER IF EXISTS recipe_search_delete;
+DROP TRIGGER IF EXISTS recipe_search_update;
+DROP TRIGGER IF EXISTS recipe_search_insert;
+
+DROP TABLE IF EXISTS RecipeSearch;
@@ -0,0 +1,69 @@
+-- Full-text index of the recipes, keyed by the recipe ID. Accents are folded
+-- by the tokenizer; stemming is approximated with prefix queries.
+CREATE VIRTUAL TABLE IF NOT EXISTS RecipeSearch USING fts5(
+    Title,
+    Description,
+    Ingredients,
+    Steps,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS recipe_search_insert AFTER INSERT ON Recipe BEGIN
+    INSERT INTO RecipeSearch (rowid, Title, Description, Ingredients, Steps) VALUES (NEW.ID, NEW.Title, NEW.Description, '', '');
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipe_search_update AFTER UPDATE OF Title, Description ON Recipe BEGIN
+    UPDATE RecipeSearch SET Title = NEW.Title, Description = NEW.Description WHERE rowid = NEW.ID;
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipe_search_delete AFTER DELETE ON Recipe BEGIN
+    DELETE FROM RecipeSearch WHERE rowid = OLD.ID;
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipeingredients_search_insert AFTER INSERT ON RecipeIngredients BEGIN
+    UPDATE RecipeSearch SET Ingredients = (
+        SELECT COALESCE(group_concat(i.Name, ' '), '') FROM RecipeIngredients ri INNER JOIN Ingredient i ON i.ID = ri.IngredientID WHERE ri.RecipeID = NEW.RecipeID
+    ) WHERE rowid = NEW.RecipeID;
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipeingredients_search_update AFTER UPDATE ON RecipeIngredients BEGIN
+    UPDATE RecipeSearch SET Ingredients = (
+        SELECT COALESCE(group_concat(i.Name, ' '), '') FROM RecipeIngredients ri INNER JOIN Ingredient i ON i.ID = ri.IngredientID WHERE ri.RecipeID = RecipeSearch.rowid
+    ) WHERE rowid IN (OLD.RecipeID, NEW.RecipeID);
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipeingredients_search_delete AFTER DELETE ON RecipeIngredients BEGIN
+    UPDATE RecipeSearch SET Ingredients = (
+        SELECT COALESCE(group_concat(i.Name, ' '), '') FROM RecipeIngredients ri INNER JOIN Ingredient i ON i.ID = ri.IngredientID WHERE ri.RecipeID = OLD.RecipeID
+    ) WHERE rowid = OLD.RecipeID;
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipesteps_search_insert AFTER INSERT ON RecipeSteps BEGIN
+    UPDATE RecipeSearch SET Steps = (
+        SELECT COALESCE(group_concat(Text, ' '), '') FROM RecipeSteps WHERE RecipeID = NEW.RecipeID
+    ) WHERE rowid = NEW.RecipeID;
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipesteps_search_update AFTER UPDATE ON RecipeSteps BEGIN
+    UPDATE RecipeSearch SET Steps = (
+        SELECT COALESCE(group_concat(Text, ' '), '') FROM RecipeSteps WHERE RecipeID = RecipeSearch.rowid
+    ) WHERE rowid IN (OLD.RecipeID, NEW.RecipeID);
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipesteps_search_delete AFTER DELETE ON RecipeSteps BEGIN
+    UPDATE RecipeSearch SET Steps = (
+        SELECT COALESCE(group_concat(Text, ' '), '') FROM RecipeSteps WHERE RecipeID = OLD.RecipeID
+    ) WHERE rowid = OLD.RecipeID;
+END;
+
+CREATE TRIGGER IF NOT EXISTS ingredient_search_update AFTER UPDATE OF Name ON Ingredient BEGIN
+    UPDATE RecipeSearch SET Ingredients = (
+        SELECT COALESCE(group_concat(i.Name, ' '), '') FROM RecipeIngredients ri INNER JOIN Ingredient i ON i.ID = ri.IngredientID WHERE ri.RecipeID = RecipeSearch.rowid
+    ) WHERE rowid IN (SELECT RecipeID FROM RecipeIngredients WHERE IngredientID = NEW.ID);
+END;
+
+INSERT INTO RecipeSearch (rowid, Title, Description, Ingredients, Steps)
+SELECT r.ID, r.Title, r.Description,
+    COALESCE((SELECT group_concat(i.Name, ' ') FROM RecipeIngredients ri INNER JOIN Ingredient i ON i.ID = ri.IngredientID WHERE ri.RecipeID = r.ID), ''),
+    COALESCE((SELECT group_concat(s.Text, ' ') FROM RecipeSteps s WHERE s.RecipeID = r.ID), '')
+FROM Recipe r;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS Users (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Email VARCHAR(255) NOT NULL UNIQUE,
+    Name VARCHAR(255) NOT NULL,
+    PasswordHash VARCHAR(255) NOT NULL,
+    Role VARCHAR(20) NOT NULL DEFAULT 'author',
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Recipes created before the users existed have no author. SQLite cannot
+-- drop a column used by a foreign key, so the reference is left unchecked.
+ALTER TABLE Recipe ADD COLUMN AuthorID INT;
+
+CREATE INDEX IF NOT EXISTS recipe_authorid_idx ON Recipe (AuthorID);
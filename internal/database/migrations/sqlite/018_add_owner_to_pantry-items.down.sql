@@ -0,0 +1,19 @@
+CREATE TABLE PantryItems_old (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    IngredientID INT NOT NULL UNIQUE,
+    Quantity FLOAT,
+    Unit VARCHAR(50),
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (IngredientID) REFERENCES Ingredient(ID)
+);
+
+-- A single pantry holds each ingredient once, so only the oldest item of
+-- each ingredient is kept
+INSERT INTO PantryItems_old (ID, IngredientID, Quantity, Unit, CreatedAt, UpdatedAt)
+SELECT ID, IngredientID, Quantity, Unit, CreatedAt, UpdatedAt FROM PantryItems p
+WHERE NOT EXISTS (SELECT 1 FROM PantryItems o WHERE o.IngredientID = p.IngredientID AND o.ID < p.ID);
+
+DROP TABLE PantryItems;
+
+ALTER TABLE PantryItems_old RENAME TO PantryItems;
@@ -0,0 +1,23 @@
+-- Each principal keeps its own pantry, identified by its subject, such as
+-- "user:1" or "api_key:2". The items stored before pantries had owners are
+-- left with an empty owner, which no principal has. SQLite cannot drop the
+-- UNIQUE constraint on IngredientID, so the table is rebuilt
+CREATE TABLE PantryItems_new (
+    ID INTEGER PRIMARY KEY AUTOINCREMENT,
+    Owner VARCHAR(255) NOT NULL DEFAULT '',
+    IngredientID INT NOT NULL,
+    Quantity FLOAT,
+    Unit VARCHAR(50),
+    CreatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UpdatedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (IngredientID) REFERENCES Ingredient(ID)
+);
+
+INSERT INTO PantryItems_new (ID, IngredientID, Quantity, Unit, CreatedAt, UpdatedAt)
+SELECT ID, IngredientID, Quantity, Unit, CreatedAt, UpdatedAt FROM PantryItems;
+
+DROP TABLE PantryItems;
+
+ALTER TABLE PantryItems_new RENAME TO PantryItems;
+
+CREATE UNIQUE INDEX IF NOT EXISTS pantryitems_owner_ingredientid_idx ON PantryItems (Owner, IngredientID);
@@ -0,0 +1,825 @@
+// Package repotest is a conformance suite for the storage backends. It runs
+// the same checks against any models.Repositories, so that the in-memory and
+// SQL implementations agree on results, errors and pagination.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// Run runs the suite. newRepositories returns repositories over an empty
+// store and is called once per test.
+func Run(t *testing.T, newRepositories func(t *testing.T) *models.Repositories) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repositories *models.Repositories)
+	}{
+		{"Categories", testCategories},
+		{"Ingredients", testIngredients},
+		{"IngredientInUse", testIngredientInUse},
+		{"Recipes", testRecipes},
+		{"RecipeStepUpdate", testRecipeStepUpdate},
+		{"RecipeSteps", testRecipeSteps},
+		{"SearchRecipes", testSearchRecipes},
+		{"RecipeMissingReference", testRecipeMissingReference},
+		{"Pagination", testPagination},
+		{"ShoppingLists", testShoppingLists},
+		{"CookableRecipes", testCookableRecipes},
+		{"Users", testUsers},
+		{"APIKeys", testAPIKeys},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t, newRepositories(t))
+		})
+	}
+}
+
+// missingID is an ID no record of the suite ever gets.
+const missingID = 999999
+
+func testCategories(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	categories := repositories.Categories
+
+	id, err := categories.CreateCategory(ctx, models.Category{Name: "Doces", Description: "Bolos e tortas"})
+	if err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+
+	category, err := categories.GetCategoryByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetCategoryByID: %v", err)
+	}
+	if category.ID != id || category.Name != "Doces" || category.Description != "Bolos e tortas" {
+		t.Errorf("GetCategoryByID = %+v, want the created category", category)
+	}
+
+	found, err := categories.GetCategoriesByIDs(ctx, []int{missingID, id})
+	if err != nil {
+		t.Fatalf("GetCategoriesByIDs: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != id || found[0].Description != "Bolos e tortas" {
+		t.Errorf("GetCategoriesByIDs = %+v, want only the created category", found)
+	}
+
+	err = categories.UpdateCategoryByID(ctx, id, models.Category{Name: "Sobremesas"})
+	if err != nil {
+		t.Fatalf("UpdateCategoryByID: %v", err)
+	}
+	category, err = categories.GetCategoryByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetCategoryByID after update: %v", err)
+	}
+	if category.Name != "Sobremesas" {
+		t.Errorf("Name after update = %q, want %q", category.Name, "Sobremesas")
+	}
+
+	err = categories.DeleteCategoryByID(ctx, id)
+	if err != nil {
+		t.Fatalf("DeleteCategoryByID: %v", err)
+	}
+
+	_, err = categories.GetCategoryByID(ctx, id)
+	wantError(t, "GetCategoryByID after delete", err, models.ErrNotFound)
+	wantError(t, "UpdateCategoryByID of a missing category", categories.UpdateCategoryByID(ctx, missingID, models.Category{Name: "x"}), models.ErrNotFound)
+	wantError(t, "DeleteCategoryByID of a missing category", categories.DeleteCategoryByID(ctx, missingID), models.ErrNotFound)
+}
+
+func testIngredients(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	ingredients := repositories.Ingredients
+
+	density := 0.55
+	flour := createIngredient(t, repositories, models.Ingredient{Name: "Farinha", Density: &density, Aisle: "Mercearia"})
+	milk := createIngredient(t, repositories, models.Ingredient{Name: "Leite", Aisle: "Laticínios"})
+
+	ingredient, err := ingredients.GetIngredientByID(ctx, flour)
+	if err != nil {
+		t.Fatalf("GetIngredientByID: %v", err)
+	}
+	if ingredient.Name != "Farinha" || ingredient.Aisle != "Mercearia" || ingredient.Density == nil || *ingredient.Density != density {
+		t.Errorf("GetIngredientByID = %+v, want the created ingredient", ingredient)
+	}
+
+	found, err := ingredients.GetIngredientsByIDs(ctx, []int{milk, missingID, flour})
+	if err != nil {
+		t.Fatalf("GetIngredientsByIDs: %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("GetIngredientsByIDs returned %d ingredients, want 2", len(found))
+	}
+
+	page, info, err := ingredients.GetAllIngredients(ctx, models.IngredientFilter{Aisle: "Laticínios"}, models.ListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAllIngredients: %v", err)
+	}
+	if len(page) != 1 || page[0].ID != milk || info.Total != 1 {
+		t.Errorf("GetAllIngredients by aisle = %+v (total %d), want only the milk", page, info.Total)
+	}
+
+	err = ingredients.UpdateIngredientByID(ctx, milk, models.Ingredient{Name: "Leite integral"})
+	if err != nil {
+		t.Fatalf("UpdateIngredientByID: %v", err)
+	}
+	ingredient, err = ingredients.GetIngredientByID(ctx, milk)
+	if err != nil {
+		t.Fatalf("GetIngredientByID after update: %v", err)
+	}
+	if ingredient.Name != "Leite integral" || ingredient.Aisle != "" {
+		t.Errorf("GetIngredientByID after update = %+v, want the new name without aisle", ingredient)
+	}
+
+	err = ingredients.DeleteIngredientByID(ctx, milk)
+	if err != nil {
+		t.Fatalf("DeleteIngredientByID: %v", err)
+	}
+
+	_, err = ingredients.GetIngredientByID(ctx, milk)
+	wantError(t, "GetIngredientByID after delete", err, models.ErrNotFound)
+	wantError(t, "DeleteIngredientByID of a missing ingredient", ingredients.DeleteIngredientByID(ctx, missingID), models.ErrNotFound)
+}
+
+func testIngredientInUse(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+
+	flour := createIngredient(t, repositories, models.Ingredient{Name: "Farinha"})
+	createRecipe(t, repositories, models.Recipe{
+		Title:       "Pão",
+		Difficulty:  models.DifficultyEasy,
+		Ingredients: []models.RecipeIngredient{{IngredientID: flour, Quantity: 500, Unit: "g"}},
+	})
+
+	err := repositories.Ingredients.DeleteIngredientByID(ctx, flour)
+	wantError(t, "DeleteIngredientByID of an ingredient in use", err, models.ErrConflict)
+
+	_, err = repositories.Ingredients.GetIngredientByID(ctx, flour)
+	if err != nil {
+		t.Errorf("GetIngredientByID after a refused delete: %v", err)
+	}
+}
+
+func testRecipes(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	recipes := repositories.Recipes
+
+	flour := createIngredient(t, repositories, models.Ingredient{Name: "Farinha"})
+	eggs := createIngredient(t, repositories, models.Ingredient{Name: "Ovos"})
+	category, err := repositories.Categories.CreateCategory(ctx, models.Category{Name: "Massas"})
+	if err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+
+	id := createRecipe(t, repositories, models.Recipe{
+		Title:       "Macarrão caseiro",
+		Description: "Massa fresca de ovos.",
+		PrepTime:    40,
+		Servings:    4,
+		Difficulty:  models.DifficultyMedium,
+		Ingredients: []models.RecipeIngredient{
+			{IngredientID: flour, Quantity: 300, Unit: "g"},
+			{IngredientID: eggs, Quantity: 3, Unit: "un", Note: "em temperatura ambiente"},
+		},
+		Categories: []models.Category{{ID: category}},
+		Steps: []models.Step{
+			{Text: "Misture a farinha com os ovos.", IngredientIDs: []int{flour, eggs}},
+			{Text: "Abra a massa e corte."},
+		},
+	})
+
+	recipe, err := recipes.GetRecipeByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetRecipeByID: %v", err)
+	}
+	if recipe.Title != "Macarrão caseiro" || recipe.PrepTime != 40 || recipe.Servings != 4 || recipe.Difficulty != models.DifficultyMedium {
+		t.Errorf("GetRecipeByID = %+v, want the created recipe", recipe)
+	}
+	if len(recipe.Ingredients) != 2 || recipe.Ingredients[0].Name == "" {
+		t.Errorf("Ingredients = %+v, want both lines with their names", recipe.Ingredients)
+	}
+	if len(recipe.Categories) != 1 || recipe.Categories[0].ID != category {
+		t.Errorf("Categories = %+v, want the category %d", recipe.Categories, category)
+	}
+	if len(recipe.Steps) != 2 || recipe.Steps[0].Text != "Misture a farinha com os ovos." || len(recipe.Steps[0].IngredientIDs) != 2 {
+		t.Errorf("Steps = %+v, want both steps in order", recipe.Steps)
+	}
+
+	recipe.Title = "Macarrão fresco"
+	recipe.Ingredients = recipe.Ingredients[:1]
+	recipe.Categories = nil
+	recipe.Steps = []models.Step{{Text: "Sove a massa."}}
+	err = recipes.UpdateRecipeByID(ctx, id, recipe)
+	if err != nil {
+		t.Fatalf("UpdateRecipeByID: %v", err)
+	}
+	recipe, err = recipes.GetRecipeByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetRecipeByID after update: %v", err)
+	}
+	if recipe.Title != "Macarrão fresco" || len(recipe.Ingredients) != 1 || len(recipe.Categories) != 0 || len(recipe.Steps) != 1 {
+		t.Errorf("GetRecipeByID after update = %+v, want the replaced associations", recipe)
+	}
+
+	page, _, err := recipes.GetAllRecipes(ctx, models.RecipeFilter{IngredientID: flour}, models.RecipeIncludes{Ingredients: true}, models.ListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAllRecipes: %v", err)
+	}
+	if len(page) != 1 || len(page[0].Ingredients) != 1 {
+		t.Errorf("GetAllRecipes by ingredient = %+v, want the recipe with its ingredients", page)
+	}
+
+	err = recipes.DeleteRecipeByID(ctx, id)
+	if err != nil {
+		t.Fatalf("DeleteRecipeByID: %v", err)
+	}
+
+	_, err = recipes.GetRecipeByID(ctx, id)
+	wantError(t, "GetRecipeByID after delete", err, models.ErrNotFound)
+	wantError(t, "UpdateRecipeByID of a missing recipe", recipes.UpdateRecipeByID(ctx, missingID, models.Recipe{Title: "x", Difficulty: models.DifficultyEasy}), models.ErrNotFound)
+	wantError(t, "DeleteRecipeByID of a missing recipe", recipes.DeleteRecipeByID(ctx, missingID), models.ErrNotFound)
+
+	// The ingredients stay in the catalog once no recipe uses them
+	err = repositories.Ingredients.DeleteIngredientByID(ctx, flour)
+	if err != nil {
+		t.Errorf("DeleteIngredientByID after the recipe is gone: %v", err)
+	}
+}
+
+func testRecipeStepUpdate(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	recipes := repositories.Recipes
+
+	flour := createIngredient(t, repositories, models.Ingredient{Name: "Farinha"})
+	eggs := createIngredient(t, repositories, models.Ingredient{Name: "Ovos"})
+	id := createRecipe(t, repositories, models.Recipe{
+		Title:       "Pão",
+		Difficulty:  models.DifficultyEasy,
+		Ingredients: []models.RecipeIngredient{{IngredientID: flour, Quantity: 500, Unit: "g"}, {IngredientID: eggs, Quantity: 1, Unit: "un"}},
+		Steps: []models.Step{
+			{Text: "Misture.", IngredientIDs: []int{flour}},
+			{Text: "Descanse."},
+			{Text: "Asse."},
+		},
+	})
+
+	recipe, err := recipes.GetRecipeByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetRecipeByID: %v", err)
+	}
+	mix, rest, bake := recipe.Steps[0], recipe.Steps[1], recipe.Steps[2]
+
+	// An edit that only touches the title keeps every step ID, so the IDs the
+	// client read still work with the reorder
+	recipe.Title = "Pão caseiro"
+	if err := recipes.UpdateRecipeByID(ctx, id, recipe); err != nil {
+		t.Fatalf("UpdateRecipeByID: %v", err)
+	}
+	if err := recipes.ReorderStepsByRecipeID(ctx, id, []int{bake.ID, mix.ID, rest.ID}); err != nil {
+		t.Fatalf("ReorderStepsByRecipeID with the IDs read before the update: %v", err)
+	}
+
+	// Steps are matched by ID: the kept ones are updated in place, the new one
+	// is added and the missing one removed
+	mix.Text = "Misture bem."
+	mix.IngredientIDs = []int{flour, eggs}
+	recipe.Steps = []models.Step{bake, mix, {Text: "Sirva."}}
+	if err := recipes.UpdateRecipeByID(ctx, id, recipe); err != nil {
+		t.Fatalf("UpdateRecipeByID with the steps changed: %v", err)
+	}
+
+	steps, err := recipes.GetStepsByRecipeID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetStepsByRecipeID: %v", err)
+	}
+	if len(steps) != 3 || steps[0].ID != bake.ID || steps[1].ID != mix.ID || steps[2].ID == rest.ID || steps[2].ID == 0 {
+		t.Fatalf("steps = %+v, want the baking and mixing steps kept and a new one", steps)
+	}
+	for i, step := range steps {
+		if step.Position != i+1 {
+			t.Errorf("steps[%d].Position = %d, want %d", i, step.Position, i+1)
+		}
+	}
+	if steps[1].Text != "Misture bem." || !slices.Equal(steps[1].IngredientIDs, []int{flour, eggs}) {
+		t.Errorf("steps[1] = %+v, want the updated text and ingredients", steps[1])
+	}
+	if steps[2].Text != "Sirva." {
+		t.Errorf("steps[2] = %+v, want the new step", steps[2])
+	}
+
+	tests := []struct {
+		name  string
+		steps []models.Step
+	}{
+		{"removed step", []models.Step{{ID: rest.ID, Text: "Descanse."}}},
+		{"step of another recipe", []models.Step{{ID: missingID, Text: "Asse."}}},
+		{"repeated step", []models.Step{{ID: bake.ID, Text: "Asse."}, {ID: bake.ID, Text: "Asse de novo."}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recipe.Steps = test.steps
+			wantError(t, "UpdateRecipeByID", recipes.UpdateRecipeByID(ctx, id, recipe), models.ErrValidation)
+		})
+	}
+}
+
+func testRecipeSteps(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	recipes := repositories.Recipes
+
+	flour := createIngredient(t, repositories, models.Ingredient{Name: "Farinha"})
+	duration, temperature := 30, 180.0
+	id := createRecipe(t, repositories, models.Recipe{
+		Title:       "Bolo",
+		Difficulty:  models.DifficultyEasy,
+		Ingredients: []models.RecipeIngredient{{IngredientID: flour, Quantity: 200, Unit: "g"}},
+		Steps: []models.Step{
+			{Text: "Peneire a farinha.", IngredientIDs: []int{flour}},
+			{Text: "Bata a massa."},
+			{Text: "Asse.", Duration: &duration, Temperature: &temperature, TemperatureUnit: "C"},
+		},
+	})
+	other := createRecipe(t, repositories, models.Recipe{Title: "Suco", Difficulty: models.DifficultyEasy})
+
+	steps, err := recipes.GetStepsByRecipeID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetStepsByRecipeID: %v", err)
+	}
+	if len(steps) != 3 || steps[0].Text != "Peneire a farinha." || !slices.Equal(steps[0].IngredientIDs, []int{flour}) {
+		t.Fatalf("GetStepsByRecipeID = %+v, want the three steps in order", steps)
+	}
+	if steps[2].Duration == nil || *steps[2].Duration != 30 || steps[2].Temperature == nil || *steps[2].Temperature != 180 || steps[2].TemperatureUnit != "C" {
+		t.Errorf("steps[2] = %+v, want its duration and temperature", steps[2])
+	}
+
+	none, err := recipes.GetStepsByRecipeID(ctx, other)
+	if err != nil {
+		t.Fatalf("GetStepsByRecipeID of a recipe without steps: %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("GetStepsByRecipeID of a recipe without steps = %+v, want none", none)
+	}
+
+	order := []int{steps[2].ID, steps[0].ID, steps[1].ID}
+	if err := recipes.ReorderStepsByRecipeID(ctx, id, order); err != nil {
+		t.Fatalf("ReorderStepsByRecipeID: %v", err)
+	}
+	reordered, err := recipes.GetStepsByRecipeID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetStepsByRecipeID after reorder: %v", err)
+	}
+	var ids []int
+	for i, step := range reordered {
+		ids = append(ids, step.ID)
+		if step.Position != i+1 {
+			t.Errorf("reordered[%d].Position = %d, want %d", i, step.Position, i+1)
+		}
+	}
+	if !slices.Equal(ids, order) {
+		t.Errorf("steps after reorder = %v, want %v", ids, order)
+	}
+
+	tests := []struct {
+		name     string
+		recipeID int
+		stepIDs  []int
+		target   error
+	}{
+		{"missing recipe", missingID, nil, models.ErrNotFound},
+		{"step left out", id, order[:2], models.ErrValidation},
+		{"step of another recipe", id, []int{order[0], order[1], missingID}, models.ErrValidation},
+		{"repeated step", id, []int{order[0], order[1], order[1]}, models.ErrValidation},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wantError(t, "ReorderStepsByRecipeID", recipes.ReorderStepsByRecipeID(ctx, test.recipeID, test.stepIDs), test.target)
+		})
+	}
+}
+
+func testSearchRecipes(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	recipes := repositories.Recipes
+
+	carrot := createIngredient(t, repositories, models.Ingredient{Name: "Cenoura"})
+	lettuce := createIngredient(t, repositories, models.Ingredient{Name: "Alface"})
+
+	// The same word in the title, the ingredients and the steps, which the
+	// backends rank in that order
+	cake := createRecipe(t, repositories, models.Recipe{
+		Title:       "Bolo de cenoura",
+		Description: "Massa fofa com cobertura de chocolate.",
+		Difficulty:  models.DifficultyEasy,
+	})
+	salad := createRecipe(t, repositories, models.Recipe{
+		Title:       "Salada colorida",
+		Difficulty:  models.DifficultyEasy,
+		Ingredients: []models.RecipeIngredient{{IngredientID: lettuce, Quantity: 1, Unit: "un"}, {IngredientID: carrot, Quantity: 1, Unit: "un"}},
+	})
+	soup := createRecipe(t, repositories, models.Recipe{
+		Title:      "Sopa de legumes",
+		Difficulty: models.DifficultyEasy,
+		Steps:      []models.Step{{Text: "Rale as cenouras e cozinhe tudo."}},
+	})
+	createRecipe(t, repositories, models.Recipe{Title: "Pão de queijo", Difficulty: models.DifficultyEasy})
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{"ranked by field", "cenoura", []int{cake, salad, soup}},
+		{"plural", "cenouras", []int{cake, salad, soup}},
+		{"every term", "cenoura sopa", []int{soup}},
+		{"excluded term", "cenoura -sopa", []int{cake, salad}},
+		{"no match", "abacaxi", nil},
+		{"only stopwords", "de", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results, info, err := recipes.SearchRecipes(ctx, test.query, models.ListOptions{Limit: 10})
+			if err != nil {
+				t.Fatalf("SearchRecipes: %v", err)
+			}
+
+			var ids []int
+			for _, result := range results {
+				ids = append(ids, result.RecipeID)
+			}
+			if !slices.Equal(ids, test.want) {
+				t.Errorf("results = %v, want %v", ids, test.want)
+			}
+			if info.Total != len(test.want) {
+				t.Errorf("Total = %d, want %d", info.Total, len(test.want))
+			}
+		})
+	}
+
+	results, info, err := recipes.SearchRecipes(ctx, "cenoura", models.ListOptions{Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("SearchRecipes with a page: %v", err)
+	}
+	if len(results) != 2 || results[0].RecipeID != salad || results[1].RecipeID != soup || info.HasNext || !info.HasPrev || info.Total != 3 {
+		t.Errorf("SearchRecipes with a page = %+v %+v, want the last two results", results, info)
+	}
+	if !strings.Contains(results[1].Snippet, "<mark>cenouras</mark>") {
+		t.Errorf("Snippet = %q, want the matched word of the step marked", results[1].Snippet)
+	}
+}
+
+func testRecipeMissingReference(t *testing.T, repositories *models.Repositories) {
+	_, err := repositories.Recipes.CreateRecipe(context.Background(), models.Recipe{
+		Title:       "Bolo",
+		Difficulty:  models.DifficultyEasy,
+		Ingredients: []models.RecipeIngredient{{IngredientID: missingID, Quantity: 1, Unit: "un"}},
+	})
+	wantError(t, "CreateRecipe with a missing ingredient", err, models.ErrValidation)
+}
+
+func testPagination(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	categories := repositories.Categories
+
+	var ids []int
+	for _, name := range []string{"Eta", "Alfa", "Delta", "Beta", "Gama"} {
+		id, err := categories.CreateCategory(ctx, models.Category{Name: name})
+		if err != nil {
+			t.Fatalf("CreateCategory: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	tests := []struct {
+		name    string
+		options models.ListOptions
+		want    []string
+		hasNext bool
+		hasPrev bool
+	}{
+		{"first page", models.ListOptions{Limit: 2}, []string{"Eta", "Alfa"}, true, false},
+		{"offset", models.ListOptions{Limit: 2, Offset: 2}, []string{"Delta", "Beta"}, true, true},
+		{"last page", models.ListOptions{Limit: 2, Offset: 4}, []string{"Gama"}, false, true},
+		{"past the end", models.ListOptions{Limit: 2, Offset: 10}, nil, false, true},
+		{"by name", models.ListOptions{Limit: 3, Sort: models.SortByName}, []string{"Alfa", "Beta", "Delta"}, true, false},
+		{"by name descending", models.ListOptions{Limit: 2, Sort: models.SortByName, Desc: true}, []string{"Gama", "Eta"}, true, false},
+		{"after a cursor", models.ListOptions{Limit: 2, Sort: models.SortByName, Cursor: &models.Cursor{ID: ids[3]}}, []string{"Delta", "Eta"}, true, true},
+		{"before a cursor", models.ListOptions{Limit: 2, Sort: models.SortByName, Cursor: &models.Cursor{ID: ids[0], Before: true}}, []string{"Beta", "Delta"}, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, info, err := categories.GetAllCategories(ctx, test.options)
+			if err != nil {
+				t.Fatalf("GetAllCategories: %v", err)
+			}
+
+			var names []string
+			for _, category := range page {
+				names = append(names, category.Name)
+			}
+			if !slices.Equal(names, test.want) {
+				t.Errorf("names = %v, want %v", names, test.want)
+			}
+			if info.Total != len(ids) || info.HasNext != test.hasNext || info.HasPrev != test.hasPrev {
+				t.Errorf("info = %+v, want total %d, next %t and prev %t", info, len(ids), test.hasNext, test.hasPrev)
+			}
+		})
+	}
+}
+
+func testShoppingLists(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	lists := repositories.ShoppingLists
+
+	flour := createIngredient(t, repositories, models.Ingredient{Name: "Farinha"})
+	id, err := lists.CreateShoppingList(ctx, models.ShoppingList{
+		Name:  "Semana",
+		Owner: "user:1",
+		Items: []models.ShoppingListItem{{IngredientID: flour, Aisle: "Mercearia", Quantity: 1.5, Unit: "kg"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateShoppingList: %v", err)
+	}
+
+	list, err := lists.GetShoppingListByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetShoppingListByID: %v", err)
+	}
+	if list.Name != "Semana" || list.Owner != "user:1" || len(list.Items) != 1 || list.Items[0].Name != "Farinha" || list.Items[0].Aisle != "Mercearia" {
+		t.Fatalf("GetShoppingListByID = %+v, want the list with its item", list)
+	}
+
+	err = lists.CheckShoppingListItem(ctx, id, list.Items[0].ID, true)
+	if err != nil {
+		t.Fatalf("CheckShoppingListItem: %v", err)
+	}
+	list, err = lists.GetShoppingListByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetShoppingListByID after check: %v", err)
+	}
+	if !list.Items[0].Checked {
+		t.Errorf("Checked = false after CheckShoppingListItem")
+	}
+	wantError(t, "CheckShoppingListItem of a missing item", lists.CheckShoppingListItem(ctx, id, missingID, true), models.ErrNotFound)
+
+	wantError(t, "DeleteIngredientByID of an ingredient in a list", repositories.Ingredients.DeleteIngredientByID(ctx, flour), models.ErrConflict)
+
+	err = lists.DeleteShoppingListByID(ctx, id)
+	if err != nil {
+		t.Fatalf("DeleteShoppingListByID: %v", err)
+	}
+	_, err = lists.GetShoppingListByID(ctx, id)
+	wantError(t, "GetShoppingListByID after delete", err, models.ErrNotFound)
+}
+
+func testCookableRecipes(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	pantry := repositories.Pantry
+
+	flour := createIngredient(t, repositories, models.Ingredient{Name: "Farinha"})
+	eggs := createIngredient(t, repositories, models.Ingredient{Name: "Ovos"})
+	milk := createIngredient(t, repositories, models.Ingredient{Name: "Leite"})
+	sugar := createIngredient(t, repositories, models.Ingredient{Name: "Açúcar"})
+
+	// Every ingredient of the pancakes is in the pantry, but not enough flour,
+	// and they use more ingredients, so they would come first on presence alone
+	pancakes := createRecipe(t, repositories, models.Recipe{
+		Title:      "Panqueca",
+		Difficulty: models.DifficultyEasy,
+		Ingredients: []models.RecipeIngredient{
+			{IngredientID: flour, Quantity: 500, Unit: "g"},
+			{IngredientID: eggs, Quantity: 3, Unit: "un"},
+			{IngredientID: milk, Quantity: 200, Unit: "ml"},
+		},
+	})
+	omelette := createRecipe(t, repositories, models.Recipe{
+		Title:      "Omelete",
+		Difficulty: models.DifficultyEasy,
+		Ingredients: []models.RecipeIngredient{
+			{IngredientID: eggs, Quantity: 2, Unit: "un"},
+			{IngredientID: sugar, Quantity: 1, Unit: "colher de chá", Optional: true},
+		},
+	})
+	syrup := createRecipe(t, repositories, models.Recipe{
+		Title:       "Calda",
+		Difficulty:  models.DifficultyEasy,
+		Ingredients: []models.RecipeIngredient{{IngredientID: sugar, Quantity: 100, Unit: "g"}},
+	})
+
+	// Each owner has its own pantry: the sugar of the other one must not
+	// make the syrup cookable
+	const owner, other = "user:1", "user:2"
+	quantity := func(q float64) *float64 { return &q }
+	for _, item := range []models.PantryItem{
+		{IngredientID: flour, Quantity: quantity(0.1), Unit: "kg"},
+		{IngredientID: eggs, Quantity: quantity(6), Unit: "un"},
+		{IngredientID: milk},
+	} {
+		if err := pantry.SetPantryItem(ctx, owner, item); err != nil {
+			t.Fatalf("SetPantryItem: %v", err)
+		}
+	}
+	if err := pantry.SetPantryItem(ctx, other, models.PantryItem{IngredientID: sugar}); err != nil {
+		t.Fatalf("SetPantryItem of the other owner: %v", err)
+	}
+	wantError(t, "SetPantryItem of a missing ingredient", pantry.SetPantryItem(ctx, owner, models.PantryItem{IngredientID: missingID}), models.ErrNotFound)
+
+	items, err := pantry.GetPantryItems(ctx, owner)
+	if err != nil {
+		t.Fatalf("GetPantryItems: %v", err)
+	}
+	if len(items) != 3 || items[0].Name != "Farinha" || items[1].Quantity != nil {
+		t.Errorf("GetPantryItems = %+v, want the three items by name", items)
+	}
+	items, err = pantry.GetPantryItems(ctx, other)
+	if err != nil {
+		t.Fatalf("GetPantryItems of the other owner: %v", err)
+	}
+	if len(items) != 1 || items[0].IngredientID != sugar {
+		t.Errorf("GetPantryItems of the other owner = %+v, want only the sugar", items)
+	}
+
+	tests := []struct {
+		name       string
+		maxMissing int
+		limit      int
+		want       []int
+	}{
+		{"cookable past the limit", 0, 1, []int{omelette}},
+		{"one missing", 1, 10, []int{omelette, pancakes, syrup}},
+		{"limit", 1, 2, []int{omelette, pancakes}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recipes, err := pantry.FindCookableRecipes(ctx, owner, test.maxMissing, test.limit)
+			if err != nil {
+				t.Fatalf("FindCookableRecipes: %v", err)
+			}
+
+			var ids []int
+			for _, recipe := range recipes {
+				ids = append(ids, recipe.RecipeID)
+			}
+			if !slices.Equal(ids, test.want) {
+				t.Errorf("recipes = %v, want %v", ids, test.want)
+			}
+		})
+	}
+
+	recipes, err := pantry.FindCookableRecipes(ctx, owner, 1, 10)
+	if err != nil {
+		t.Fatalf("FindCookableRecipes: %v", err)
+	}
+	if len(recipes) != 3 || recipes[0].TotalIngredients != 1 || len(recipes[0].Missing) != 0 {
+		t.Errorf("omelette = %+v, want one required ingredient and none missing", recipes)
+	} else if recipes[1].TotalIngredients != 3 || len(recipes[1].Missing) != 1 || recipes[1].Missing[0].IngredientID != flour {
+		t.Errorf("pancakes = %+v, want the flour missing", recipes[1])
+	}
+
+	recipes, err = pantry.FindCookableRecipes(ctx, other, 0, 10)
+	if err != nil {
+		t.Fatalf("FindCookableRecipes of the other owner: %v", err)
+	}
+	if len(recipes) != 1 || recipes[0].RecipeID != syrup {
+		t.Errorf("FindCookableRecipes of the other owner = %+v, want only the syrup", recipes)
+	}
+
+	err = pantry.DeletePantryItem(ctx, owner, eggs)
+	if err != nil {
+		t.Fatalf("DeletePantryItem: %v", err)
+	}
+	recipes, err = pantry.FindCookableRecipes(ctx, owner, 0, 10)
+	if err != nil {
+		t.Fatalf("FindCookableRecipes after delete: %v", err)
+	}
+	if len(recipes) != 0 {
+		t.Errorf("FindCookableRecipes after delete = %+v, want none", recipes)
+	}
+}
+
+func testUsers(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	users := repositories.Users
+
+	id, err := users.CreateUser(ctx, models.User{Email: "ana@example.com", Name: "Ana", PasswordHash: "hash", Role: models.RoleAuthor})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	user, err := users.GetUserByEmail(ctx, "ana@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail: %v", err)
+	}
+	if user.ID != id || user.Name != "Ana" || user.PasswordHash != "hash" || user.Role != models.RoleAuthor {
+		t.Errorf("GetUserByEmail = %+v, want the created user", user)
+	}
+
+	_, err = users.CreateUser(ctx, models.User{Email: "ana@example.com", Name: "Outra Ana", PasswordHash: "hash", Role: models.RoleAuthor})
+	wantError(t, "CreateUser with a taken e-mail", err, models.ErrConflict)
+
+	err = users.SetUserRole(ctx, id, models.RoleEditor)
+	if err != nil {
+		t.Fatalf("SetUserRole: %v", err)
+	}
+	user, err = users.GetUserByID(ctx, id)
+	if err != nil {
+		t.Fatalf("GetUserByID: %v", err)
+	}
+	if user.Role != models.RoleEditor {
+		t.Errorf("Role = %q, want %q", user.Role, models.RoleEditor)
+	}
+
+	_, err = users.GetUserByID(ctx, missingID)
+	wantError(t, "GetUserByID of a missing user", err, models.ErrNotFound)
+	_, err = users.GetUserByEmail(ctx, "nobody@example.com")
+	wantError(t, "GetUserByEmail of a missing user", err, models.ErrNotFound)
+	wantError(t, "SetUserRole of a missing user", users.SetUserRole(ctx, missingID, models.RoleAdmin), models.ErrNotFound)
+}
+
+func testAPIKeys(t *testing.T, repositories *models.Repositories) {
+	ctx := context.Background()
+	keys := repositories.APIKeys
+
+	id, err := keys.CreateAPIKey(ctx, models.APIKey{Name: "ci", Hash: "0123abcd", Role: models.RoleEditor})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	key, err := keys.GetAPIKeyByHash(ctx, "0123abcd")
+	if err != nil {
+		t.Fatalf("GetAPIKeyByHash: %v", err)
+	}
+	if key.ID != id || key.Name != "ci" || key.Role != models.RoleEditor || key.Revoked() {
+		t.Errorf("GetAPIKeyByHash = %+v, want the created key", key)
+	}
+
+	err = keys.SetAPIKeyRole(ctx, id, models.RoleAdmin)
+	if err != nil {
+		t.Fatalf("SetAPIKeyRole: %v", err)
+	}
+	key, err = keys.GetAPIKeyByHash(ctx, "0123abcd")
+	if err != nil {
+		t.Fatalf("GetAPIKeyByHash after SetAPIKeyRole: %v", err)
+	}
+	if key.Role != models.RoleAdmin {
+		t.Errorf("Role = %q, want %q", key.Role, models.RoleAdmin)
+	}
+
+	err = keys.RevokeAPIKey(ctx, id)
+	if err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	key, err = keys.GetAPIKeyByHash(ctx, "0123abcd")
+	if err != nil {
+		t.Fatalf("GetAPIKeyByHash after revoke: %v", err)
+	}
+	if !key.Revoked() {
+		t.Errorf("Revoked() = false after RevokeAPIKey")
+	}
+
+	_, err = keys.GetAPIKeyByHash(ctx, "ffff")
+	wantError(t, "GetAPIKeyByHash of a missing key", err, models.ErrNotFound)
+	wantError(t, "RevokeAPIKey of a missing key", keys.RevokeAPIKey(ctx, missingID), models.ErrNotFound)
+	wantError(t, "SetAPIKeyRole of a missing key", keys.SetAPIKeyRole(ctx, missingID, models.RoleAdmin), models.ErrNotFound)
+}
+
+func createIngredient(t *testing.T, repositories *models.Repositories, ingredient models.Ingredient) int {
+	t.Helper()
+
+	id, err := repositories.Ingredients.CreateIngredient(context.Background(), ingredient)
+	if err != nil {
+		t.Fatalf("CreateIngredient: %v", err)
+	}
+
+	return id
+}
+
+func createRecipe(t *testing.T, repositories *models.Repositories, recipe models.Recipe) int {
+	t.Helper()
+
+	id, err := repositories.Recipes.CreateRecipe(context.Background(), recipe)
+	if err != nil {
+		t.Fatalf("CreateRecipe: %v", err)
+	}
+
+	return id
+}
+
+// wantError reports an error unless err matches target.
+func wantError(t *testing.T, what string, err error, target error) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Errorf("%s: error = %v, want %v", what, err, target)
+	}
+}
@@ -0,0 +1,76 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// APIKeyRepository is the SQL implementation of models.APIKeyRepository.
+type APIKeyRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewAPIKeyRepository creates an APIKeyRepository backed by db.
+func NewAPIKeyRepository(db *sql.DB, dialect Dialect) *APIKeyRepository {
+	return &APIKeyRepository{db: db, dialect: dialect}
+}
+
+// GetAPIKeyByHash retrieves the API key with the hash from the database.
+func (ar *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	var key models.APIKey
+	var revokedAt sql.NullTime
+
+	err := ar.db.QueryRowContext(ctx, "SELECT id, name, keyhash, role, createdat, revokedat FROM apikeys WHERE keyhash = $1", hash).
+		Scan(&key.ID, &key.Name, &key.Hash, &key.Role, &key.CreatedAt, &revokedAt)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return models.APIKey{}, fmt.Errorf("api key %w", models.ErrNotFound)
+	case err != nil:
+		return models.APIKey{}, err
+	}
+
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return key, nil
+}
+
+// CreateAPIKey stores an API key in the database.
+func (ar *APIKeyRepository) CreateAPIKey(ctx context.Context, key models.APIKey) (int, error) {
+	var id int
+
+	err := ar.db.QueryRowContext(ctx, "INSERT INTO apikeys (name, keyhash, role, createdat) VALUES ($1, $2, $3, $4) RETURNING id",
+		key.Name, key.Hash, key.Role, time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// RevokeAPIKey revokes an API key in the database.
+func (ar *APIKeyRepository) RevokeAPIKey(ctx context.Context, id int) error {
+	result, err := ar.db.ExecContext(ctx, "UPDATE apikeys SET revokedat = COALESCE(revokedat, $1) WHERE id = $2", time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(result, "api key", id)
+}
+
+// SetAPIKeyRole changes the role of an API key in the database.
+func (ar *APIKeyRepository) SetAPIKeyRole(ctx context.Context, id int, role string) error {
+	result, err := ar.db.ExecContext(ctx, "UPDATE apikeys SET role = $1 WHERE id = $2", role, id)
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(result, "api key", id)
+}
@@ -0,0 +1,241 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// CategoryRepository is the SQL implementation of models.CategoryRepository.
+type CategoryRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewCategoryRepository creates a CategoryRepository backed by db.
+func NewCategoryRepository(db *sql.DB, dialect Dialect) *CategoryRepository {
+	return &CategoryRepository{db: db, dialect: dialect}
+}
+
+func (cr *CategoryRepository) GetCategoryByID(ctx context.Context, id int) (models.Category, error) {
+	var category models.Category
+
+	err := cr.db.QueryRowContext(ctx, "SELECT id, name, COALESCE(description, ''), createdat, updatedat FROM category WHERE id = $1", id).
+		Scan(&category.ID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return models.Category{}, models.NewNotFoundError("category", id)
+	case err != nil:
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+// GetCategoriesByIDs retrieves the categories with the given IDs from the database.
+func (cr *CategoryRepository) GetCategoriesByIDs(ctx context.Context, ids []int) ([]models.Category, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	condition, args := cr.dialect.inIDs("id", ids)
+	rows, err := cr.db.QueryContext(ctx, "SELECT id, name, COALESCE(description, ''), createdat, updatedat FROM category WHERE "+condition+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		var category models.Category
+		err := rows.Scan(&category.ID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+func (cr *CategoryRepository) UpdateCategoryByID(ctx context.Context, id int, updatedCategory models.Category) error {
+	result, err := cr.db.ExecContext(ctx, "UPDATE category SET name=$1, description=$2, updatedat=$3 WHERE id=$4",
+		updatedCategory.Name, updatedCategory.Description, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(result, "category", id)
+}
+
+func (cr *CategoryRepository) DeleteCategoryByID(ctx context.Context, id int) error {
+	result, err := cr.db.ExecContext(ctx, "DELETE FROM category WHERE id=$1", id)
+	if err != nil {
+		return inUseError(cr.dialect, err, "category", id)
+	}
+
+	return notFoundIfNone(result, "category", id)
+}
+
+// categorySortColumns maps the sort keys of categories to their columns.
+var categorySortColumns = map[string]string{
+	models.SortByID:        "id",
+	models.SortByName:      "name",
+	models.SortByCreatedAt: "createdat",
+}
+
+// GetAllCategories retrieves a page of the categories from the database.
+func (cr *CategoryRepository) GetAllCategories(ctx context.Context, options models.ListOptions) ([]models.Category, models.PageInfo, error) {
+	query := listQuery{table: "category"}
+
+	total, err := query.count(ctx, cr.db)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	rows, err := cr.db.QueryContext(ctx, "SELECT id, name, COALESCE(description, ''), createdat, updatedat FROM category"+
+		query.page(categorySortColumns[options.Sort], options), query.args...)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	categories, err := scanCategories(rows)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	categories, info := models.Page(options, categories, total)
+
+	return categories, info, nil
+}
+
+func (cr *CategoryRepository) CreateCategory(ctx context.Context, category models.Category) (int, error) {
+	var id int
+
+	err := cr.db.QueryRowContext(ctx, "INSERT INTO category (name, description, createdat, updatedat) VALUES ($1, $2, $3, $4) RETURNING id",
+		category.Name, category.Description, time.Now(), time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (cr *CategoryRepository) GetCategoriesByRecipeID(ctx context.Context, recipeID int) ([]models.Category, error) {
+	return getCategoriesByRecipeID(ctx, cr.db, cr.dialect, recipeID)
+}
+
+func (cr *CategoryRepository) UpdateCategoriesByRecipeID(ctx context.Context, recipeID int, updatedCategories []models.Category) error {
+	return withTx(ctx, cr.db, func(tx *sql.Tx) error {
+		return replaceCategoriesByRecipeID(ctx, tx, recipeID, updatedCategories)
+	})
+}
+
+func (cr *CategoryRepository) DeleteCategoryByRecipeID(ctx context.Context, recipeID int) error {
+	return deleteCategoriesByRecipeID(ctx, cr.db, recipeID)
+}
+
+// getCategoriesByRecipeID retrieves the categories associated with a recipe.
+func getCategoriesByRecipeID(ctx context.Context, q queryer, d Dialect, recipeID int) ([]models.Category, error) {
+	categories, err := getCategoriesByRecipeIDs(ctx, q, d, []int{recipeID})
+	return categories[recipeID], err
+}
+
+// getCategoriesByRecipeIDs retrieves the categories of several recipes in one
+// query, keyed by recipe ID.
+func getCategoriesByRecipeIDs(ctx context.Context, q queryer, d Dialect, recipeIDs []int) (map[int][]models.Category, error) {
+	categories := make(map[int][]models.Category)
+	if len(recipeIDs) == 0 {
+		return categories, nil
+	}
+
+	condition, args := d.inIDs("rc.recipeid", recipeIDs)
+	query := `
+		SELECT rc.recipeid, c.id, c.name, COALESCE(c.description, ''), c.createdat, c.updatedat
+		FROM category c
+		INNER JOIN recipecategories rc ON c.id = rc.categoryid
+		WHERE ` + condition + `
+		ORDER BY rc.id
+	`
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipeID int
+		var category models.Category
+		err := rows.Scan(&recipeID, &category.ID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		categories[recipeID] = append(categories[recipeID], category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// replaceCategoriesByRecipeID removes the current category links of a recipe and inserts the given ones.
+func replaceCategoriesByRecipeID(ctx context.Context, tx *sql.Tx, recipeID int, categories []models.Category) error {
+	if err := deleteCategoriesByRecipeID(ctx, tx, recipeID); err != nil {
+		return err
+	}
+
+	return insertCategoriesByRecipeID(ctx, tx, recipeID, categories)
+}
+
+// insertCategoriesByRecipeID associates the given categories with a recipe.
+func insertCategoriesByRecipeID(ctx context.Context, tx *sql.Tx, recipeID int, categories []models.Category) error {
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO recipecategories (recipeid, categoryid, createdat, updatedat) VALUES ($1, $2, $3, $4)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, category := range categories {
+		_, err := stmt.ExecContext(ctx, recipeID, category.ID, time.Now(), time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteCategoriesByRecipeID removes every category link of a recipe.
+func deleteCategoriesByRecipeID(ctx context.Context, q queryer, recipeID int) error {
+	_, err := q.ExecContext(ctx, "DELETE FROM recipecategories WHERE recipeid = $1", recipeID)
+	return err
+}
+
+func scanCategories(rows *sql.Rows) ([]models.Category, error) {
+	var categories []models.Category
+
+	for rows.Next() {
+		var category models.Category
+		err := rows.Scan(&category.ID, &category.Name, &category.Description, &category.CreatedAt, &category.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,65 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// duplicateError turns a unique constraint failure into a conflict with the
+// formatted message.
+func duplicateError(d Dialect, err error, format string, args ...any) error {
+	if d.violation(err) == uniqueConstraint {
+		return models.NewConflictError(format, args...)
+	}
+
+	return err
+}
+
+// referenceError turns a foreign key violation while writing rows into a
+// validation error, since the input references a record that does not exist.
+func referenceError(d Dialect, err error) error {
+	if d.violation(err) == foreignKeyConstraint {
+		return models.NewValidationError(models.FieldError{Code: models.FieldNotFound, Message: "a referenced record does not exist"})
+	}
+
+	return err
+}
+
+// inUseError turns a foreign key violation while deleting a record into a
+// conflict, since other records still reference it.
+func inUseError(d Dialect, err error, resource string, id int) error {
+	if d.violation(err) == foreignKeyConstraint {
+		return models.NewConflictError("%s with ID %d is still in use", resource, id)
+	}
+
+	return err
+}
+
+// checkExists returns a NotFoundError unless table has a row with id.
+func checkExists(ctx context.Context, q queryer, table string, resource string, id int) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1)", id).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return models.NewNotFoundError(resource, id)
+	}
+
+	return nil
+}
+
+// notFoundIfNone returns a NotFoundError when result changed no row.
+func notFoundIfNone(result sql.Result, resource string, id int) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.NewNotFoundError(resource, id)
+	}
+
+	return nil
+}
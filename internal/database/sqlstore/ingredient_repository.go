@@ -0,0 +1,240 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// IngredientRepository is the SQL implementation of models.IngredientRepository.
+type IngredientRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewIngredientRepository creates an IngredientRepository backed by db.
+func NewIngredientRepository(db *sql.DB, dialect Dialect) *IngredientRepository {
+	return &IngredientRepository{db: db, dialect: dialect}
+}
+
+func (ir *IngredientRepository) GetIngredientByID(ctx context.Context, id int) (models.Ingredient, error) {
+	var ingredient models.Ingredient
+
+	err := ir.db.QueryRowContext(ctx, "SELECT id, name, density, COALESCE(aisle, ''), createdat, updatedat FROM ingredient WHERE id = $1", id).
+		Scan(&ingredient.ID, &ingredient.Name, &ingredient.Density, &ingredient.Aisle, &ingredient.CreatedAt, &ingredient.UpdatedAt)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return models.Ingredient{}, models.NewNotFoundError("ingredient", id)
+	case err != nil:
+		return models.Ingredient{}, err
+	}
+
+	return ingredient, nil
+}
+
+// GetIngredientsByIDs retrieves the ingredients with the given IDs from the database.
+func (ir *IngredientRepository) GetIngredientsByIDs(ctx context.Context, ids []int) ([]models.Ingredient, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	condition, args := ir.dialect.inIDs("id", ids)
+	rows, err := ir.db.QueryContext(ctx, "SELECT id, name, density, COALESCE(aisle, ''), createdat, updatedat FROM ingredient WHERE "+condition+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ingredients []models.Ingredient
+	for rows.Next() {
+		var ingredient models.Ingredient
+		err := rows.Scan(&ingredient.ID, &ingredient.Name, &ingredient.Density, &ingredient.Aisle, &ingredient.CreatedAt, &ingredient.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		ingredients = append(ingredients, ingredient)
+	}
+
+	return ingredients, rows.Err()
+}
+
+func (ir *IngredientRepository) UpdateIngredientByID(ctx context.Context, id int, updatedIngredient models.Ingredient) error {
+	result, err := ir.db.ExecContext(ctx, "UPDATE ingredient SET name=$1, density=$2, aisle=$3, updatedat=$4 WHERE id=$5",
+		updatedIngredient.Name, updatedIngredient.Density, updatedIngredient.Aisle, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(result, "ingredient", id)
+}
+
+func (ir *IngredientRepository) DeleteIngredientByID(ctx context.Context, id int) error {
+	result, err := ir.db.ExecContext(ctx, "DELETE FROM ingredient WHERE id=$1", id)
+	if err != nil {
+		return inUseError(ir.dialect, err, "ingredient", id)
+	}
+
+	return notFoundIfNone(result, "ingredient", id)
+}
+
+// ingredientSortColumns maps the sort keys of ingredients to their columns.
+var ingredientSortColumns = map[string]string{
+	models.SortByID:        "id",
+	models.SortByName:      "name",
+	models.SortByCreatedAt: "createdat",
+}
+
+// GetAllIngredients retrieves a page of the ingredients matching filter from the database.
+func (ir *IngredientRepository) GetAllIngredients(ctx context.Context, filter models.IngredientFilter, options models.ListOptions) ([]models.Ingredient, models.PageInfo, error) {
+	var ingredients []models.Ingredient
+
+	query := listQuery{table: "ingredient"}
+	if filter.Aisle != "" {
+		query.filter("aisle = " + query.arg(filter.Aisle))
+	}
+
+	total, err := query.count(ctx, ir.db)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	rows, err := ir.db.QueryContext(ctx, "SELECT id, name, density, COALESCE(aisle, ''), createdat, updatedat FROM ingredient"+
+		query.page(ingredientSortColumns[options.Sort], options), query.args...)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ingredient models.Ingredient
+		err := rows.Scan(&ingredient.ID, &ingredient.Name, &ingredient.Density, &ingredient.Aisle, &ingredient.CreatedAt, &ingredient.UpdatedAt)
+		if err != nil {
+			return nil, models.PageInfo{}, err
+		}
+
+		ingredients = append(ingredients, ingredient)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	ingredients, info := models.Page(options, ingredients, total)
+
+	return ingredients, info, nil
+}
+
+// CreateIngredient creates a new ingredient in the database.
+func (ir *IngredientRepository) CreateIngredient(ctx context.Context, ingredient models.Ingredient) (int, error) {
+	var id int
+
+	err := ir.db.QueryRowContext(ctx, "INSERT INTO ingredient (name, density, aisle, createdat, updatedat) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		ingredient.Name, ingredient.Density, ingredient.Aisle, time.Now(), time.Now()).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// GetIngredientsByRecipeID retrieves the ingredient lines of a recipe from the database.
+func (ir *IngredientRepository) GetIngredientsByRecipeID(ctx context.Context, recipeID int) ([]models.RecipeIngredient, error) {
+	return getIngredientsByRecipeID(ctx, ir.db, ir.dialect, recipeID)
+}
+
+// UpdateIngredientsByRecipeID replaces the ingredient lines of a recipe in the database.
+func (ir *IngredientRepository) UpdateIngredientsByRecipeID(ctx context.Context, recipeID int, updatedIngredients []models.RecipeIngredient) error {
+	return withTx(ctx, ir.db, func(tx *sql.Tx) error {
+		return replaceIngredientsByRecipeID(ctx, tx, recipeID, updatedIngredients)
+	})
+}
+
+// DeleteIngredientsByRecipeID deletes the ingredient lines of a recipe from the database.
+// The ingredients themselves stay in the catalog, since other recipes may use them.
+func (ir *IngredientRepository) DeleteIngredientsByRecipeID(ctx context.Context, recipeID int) error {
+	return deleteIngredientsByRecipeID(ctx, ir.db, recipeID)
+}
+
+// getIngredientsByRecipeID retrieves the ingredient lines of a recipe with the catalog name of each ingredient.
+func getIngredientsByRecipeID(ctx context.Context, q queryer, d Dialect, recipeID int) ([]models.RecipeIngredient, error) {
+	ingredients, err := getIngredientsByRecipeIDs(ctx, q, d, []int{recipeID})
+	return ingredients[recipeID], err
+}
+
+// getIngredientsByRecipeIDs retrieves the ingredient lines of several recipes
+// in one query, keyed by recipe ID.
+func getIngredientsByRecipeIDs(ctx context.Context, q queryer, d Dialect, recipeIDs []int) (map[int][]models.RecipeIngredient, error) {
+	ingredients := make(map[int][]models.RecipeIngredient)
+	if len(recipeIDs) == 0 {
+		return ingredients, nil
+	}
+
+	condition, args := d.inIDs("ri.recipeid", recipeIDs)
+	query := `
+		SELECT ri.recipeid, ri.ingredientid, i.name, COALESCE(ri.quantity, 0), COALESCE(ri.unit, ''), COALESCE(ri.note, ''), ri.optional
+		FROM recipeingredients ri
+		INNER JOIN ingredient i ON i.id = ri.ingredientid
+		WHERE ` + condition + `
+		ORDER BY ri.id
+	`
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipeID int
+		var ingredient models.RecipeIngredient
+		err := rows.Scan(&recipeID, &ingredient.IngredientID, &ingredient.Name, &ingredient.Quantity, &ingredient.Unit, &ingredient.Note, &ingredient.Optional)
+		if err != nil {
+			return nil, err
+		}
+
+		ingredients[recipeID] = append(ingredients[recipeID], ingredient)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ingredients, nil
+}
+
+// replaceIngredientsByRecipeID removes the current ingredient lines of a recipe and inserts the given ones.
+func replaceIngredientsByRecipeID(ctx context.Context, tx *sql.Tx, recipeID int, ingredients []models.RecipeIngredient) error {
+	if err := deleteIngredientsByRecipeID(ctx, tx, recipeID); err != nil {
+		return err
+	}
+
+	return insertIngredientsByRecipeID(ctx, tx, recipeID, ingredients)
+}
+
+// insertIngredientsByRecipeID inserts the ingredient lines of a recipe.
+func insertIngredientsByRecipeID(ctx context.Context, tx *sql.Tx, recipeID int, ingredients []models.RecipeIngredient) error {
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO recipeingredients (recipeid, ingredientid, quantity, unit, note, optional, createdat, updatedat) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, ingredient := range ingredients {
+		_, err := stmt.ExecContext(ctx, recipeID, ingredient.IngredientID, ingredient.Quantity, ingredient.Unit, ingredient.Note, ingredient.Optional, time.Now(), time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteIngredientsByRecipeID removes every ingredient line of a recipe.
+func deleteIngredientsByRecipeID(ctx context.Context, q queryer, recipeID int) error {
+	_, err := q.ExecContext(ctx, "DELETE FROM recipeingredients WHERE recipeid = $1", recipeID)
+	return err
+}
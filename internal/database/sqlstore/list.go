@@ -0,0 +1,72 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// listQuery builds the filters, keyset condition, ordering and limit of a
+// collection read on table.
+type listQuery struct {
+	table string
+	where []string
+	args  []any
+}
+
+// arg adds a query argument and returns its placeholder.
+func (q *listQuery) arg(value any) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// filter adds a condition the rows must match.
+func (q *listQuery) filter(condition string) {
+	q.where = append(q.where, condition)
+}
+
+func (q *listQuery) whereClause() string {
+	if len(q.where) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(q.where, " AND ")
+}
+
+// count returns the number of rows matching the filters.
+func (q *listQuery) count(ctx context.Context, db queryer) (int, error) {
+	var total int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+q.table+q.whereClause(), q.args...).Scan(&total)
+	return total, err
+}
+
+// page returns the clauses selecting one more row than the page size, sorted
+// by column and then by ID. It adds the cursor condition to the filters, so
+// it must be called after count. An empty column sorts by ID only.
+func (q *listQuery) page(column string, options models.ListOptions) string {
+	if column == "" {
+		column = "id"
+	}
+
+	order, comparison := "ASC", ">"
+	if options.Desc != options.Backwards() {
+		order, comparison = "DESC", "<"
+	}
+
+	// The cursor row is looked up again so that its sort value never has to
+	// travel in the cursor itself
+	if options.Cursor != nil {
+		q.filter(fmt.Sprintf("(%s, id) %s (SELECT %s, id FROM %s WHERE id = %s)",
+			column, comparison, column, q.table, q.arg(options.Cursor.ID)))
+	}
+
+	clauses := fmt.Sprintf("%s ORDER BY %s %s, id %s LIMIT %s", q.whereClause(), column, order, order, q.arg(options.Limit+1))
+	if options.Cursor == nil && options.Offset > 0 {
+		clauses += " OFFSET " + q.arg(options.Offset)
+	}
+
+	return clauses
+}
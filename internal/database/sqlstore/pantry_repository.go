@@ -0,0 +1,150 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// PantryRepository is the SQL implementation of models.PantryRepository.
+type PantryRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewPantryRepository creates a PantryRepository backed by db.
+func NewPantryRepository(db *sql.DB, dialect Dialect) *PantryRepository {
+	return &PantryRepository{db: db, dialect: dialect}
+}
+
+// GetPantryItems retrieves every item in the pantry of owner from the database.
+func (pr *PantryRepository) GetPantryItems(ctx context.Context, owner string) ([]models.PantryItem, error) {
+	var items []models.PantryItem
+
+	rows, err := pr.db.QueryContext(ctx, `
+		SELECT p.ingredientid, i.name, p.quantity, COALESCE(p.unit, '')
+		FROM pantryitems p
+		INNER JOIN ingredient i ON i.id = p.ingredientid
+		WHERE p.owner = $1
+		ORDER BY i.name
+	`, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.PantryItem
+		err := rows.Scan(&item.IngredientID, &item.Name, &item.Quantity, &item.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// SetPantryItem adds an ingredient to the pantry of owner or replaces its
+// quantity.
+func (pr *PantryRepository) SetPantryItem(ctx context.Context, owner string, item models.PantryItem) error {
+	if err := checkExists(ctx, pr.db, "ingredient", "ingredient", item.IngredientID); err != nil {
+		return err
+	}
+
+	_, err := pr.db.ExecContext(ctx, `
+		INSERT INTO pantryitems (owner, ingredientid, quantity, unit, createdat, updatedat) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (owner, ingredientid) DO UPDATE SET quantity = excluded.quantity, unit = excluded.unit, updatedat = excluded.updatedat
+	`, owner, item.IngredientID, item.Quantity, item.Unit, time.Now(), time.Now())
+	return err
+}
+
+// DeletePantryItem removes an ingredient from the pantry of owner.
+func (pr *PantryRepository) DeletePantryItem(ctx context.Context, owner string, ingredientID int) error {
+	_, err := pr.db.ExecContext(ctx, "DELETE FROM pantryitems WHERE owner = $1 AND ingredientid = $2", owner, ingredientID)
+	return err
+}
+
+// FindCookableRecipes loads the required ingredient lines of the recipes
+// with at most maxMissing ingredients absent from the pantry of owner, in a single
+// query, then checks the quantities and ranks them before keeping the first
+// limit. The quantities are converted between units in Go, so the query
+// cannot limit the recipes itself.
+func (pr *PantryRepository) FindCookableRecipes(ctx context.Context, owner string, maxMissing int, limit int) ([]models.CookableRecipe, error) {
+	rows, err := pr.db.QueryContext(ctx, `
+		SELECT ri.recipeid, r.title, ri.ingredientid, i.name, COALESCE(ri.quantity, 0), COALESCE(ri.unit, ''), COALESCE(ri.note, ''),
+			p.ingredientid IS NOT NULL, p.quantity, COALESCE(p.unit, '')
+		FROM recipeingredients ri
+		INNER JOIN recipe r ON r.id = ri.recipeid
+		INNER JOIN ingredient i ON i.id = ri.ingredientid
+		LEFT JOIN pantryitems p ON p.owner = $1 AND p.ingredientid = ri.ingredientid
+		WHERE NOT ri.optional AND ri.recipeid IN (
+			SELECT c.recipeid
+			FROM recipeingredients c
+			LEFT JOIN pantryitems cp ON cp.owner = $1 AND cp.ingredientid = c.ingredientid
+			WHERE NOT c.optional
+			GROUP BY c.recipeid
+			HAVING COUNT(*) - COUNT(cp.ingredientid) <= $2
+		)
+		ORDER BY ri.id
+	`, owner, maxMissing)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes, err := scanCookableRecipes(rows, maxMissing)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipes) > limit {
+		recipes = recipes[:limit]
+	}
+
+	return recipes, nil
+}
+
+// scanCookableRecipes builds the matched recipes from rows of ingredient
+// lines joined with the pantry and ranks them.
+func scanCookableRecipes(rows *sql.Rows, maxMissing int) ([]models.CookableRecipe, error) {
+	var recipes []models.CookableRecipe
+	index := make(map[int]int)
+
+	for rows.Next() {
+		var recipeID int
+		var title string
+		var line models.RecipeIngredient
+		var inPantry bool
+		var item models.PantryItem
+		err := rows.Scan(&recipeID, &title, &line.IngredientID, &line.Name, &line.Quantity, &line.Unit, &line.Note,
+			&inPantry, &item.Quantity, &item.Unit)
+		if err != nil {
+			return nil, err
+		}
+
+		i, ok := index[recipeID]
+		if !ok {
+			i = len(recipes)
+			index[recipeID] = i
+			recipes = append(recipes, models.CookableRecipe{RecipeID: recipeID, Title: title, Missing: []models.RecipeIngredient{}})
+		}
+
+		recipes[i].TotalIngredients++
+		if !inPantry || !item.Covers(line) {
+			recipes[i].Missing = append(recipes[i].Missing, line)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.RankCookableRecipes(recipes, maxMissing), nil
+}
@@ -0,0 +1,74 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/keevferreira/recipes-api/internal/tracing"
+	"github.com/keevferreira/recipes-api/internal/utils"
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func ConnectToPostgresDB(connectionString string) (*sql.DB, error) {
+	db, err := tracing.OpenDB("postgres", connectionString, semconv.DBSystemPostgreSQL)
+	if err != nil {
+		return nil, utils.WrapError(err, "Falha ao conectar ao banco de dados")
+	}
+
+	// sql.Open only validates its arguments; the ping actually reaches the server.
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, utils.WrapError(err, "Falha ao conectar ao banco de dados")
+	}
+	slog.Info("conexão com o banco de dados estabelecida", slog.String("driver", "postgres"))
+	return db, nil
+}
+
+func DisconnectPostgresDB(DB *sql.DB) error {
+	if DB != nil {
+		if err := DB.Close(); err != nil {
+			return err
+		}
+		slog.Info("disconnected from the database", slog.String("driver", "postgres"))
+	}
+	return nil
+}
+
+// Postgres is the dialect of PostgreSQL.
+var Postgres Dialect = postgresDialect{}
+
+type postgresDialect struct{}
+
+// SQLSTATEs of the constraint failures.
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// inIDs matches column against an array holding ids, in a single placeholder.
+func (postgresDialect) inIDs(column string, ids []int) (string, []any) {
+	values := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, int64(id))
+	}
+
+	return column + " = ANY($1)", []any{pq.Array(values)}
+}
+
+func (postgresDialect) violation(err error) constraint {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return noConstraint
+	}
+
+	switch pqErr.Code {
+	case foreignKeyViolation:
+		return foreignKeyConstraint
+	case uniqueViolation:
+		return uniqueConstraint
+	}
+
+	return noConstraint
+}
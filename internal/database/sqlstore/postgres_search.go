@@ -0,0 +1,62 @@
+package sqlstore
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/keevferreira/recipes-api/internal/logging"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// searchHeadlineOptions configures the snippets returned by SearchRecipes.
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MinWords=8, MaxWords=24, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// escapeHTML wraps a SQL text expression so it comes out HTML escaped like
+// html.EscapeString, as ts_headline copies its input as is.
+func escapeHTML(expr string) string {
+	return `replace(replace(replace(replace(replace(` + expr +
+		`, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;')`
+}
+
+// searchRecipes searches the recipes through their search vector, kept up to
+// date by the triggers of the 013 migration.
+func (postgresDialect) searchRecipes(ctx context.Context, db queryer, query string, options models.ListOptions) ([]models.RecipeSearchResult, models.PageInfo, error) {
+	var total int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM recipe WHERE searchvector @@ websearch_to_tsquery('portuguese_unaccent', $1)", query).Scan(&total)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT r.id, r.title, ts_rank(r.searchvector, q),
+			ts_headline('portuguese_unaccent', `+escapeHTML(`concat_ws(' ', r.description,
+				(SELECT string_agg(s.text, ' ' ORDER BY s.position) FROM recipesteps s WHERE s.recipeid = r.id))`)+`, q, $2)
+		FROM recipe r, websearch_to_tsquery('portuguese_unaccent', $1) q
+		WHERE r.searchvector @@ q
+		ORDER BY 3 DESC, r.id
+		LIMIT $3 OFFSET $4
+	`, query, searchHeadlineOptions, options.Limit+1, options.Offset)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var results []models.RecipeSearchResult
+	for rows.Next() {
+		var result models.RecipeSearchResult
+		if err := rows.Scan(&result.RecipeID, &result.Title, &result.Rank, &result.Snippet); err != nil {
+			return nil, models.PageInfo{}, err
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	results, info := models.Page(options, results, total)
+	logging.FromContext(ctx).Debug("recipe search", slog.String("query", query), slog.Int("total", total))
+
+	return results, info, nil
+}
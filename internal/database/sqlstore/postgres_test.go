@@ -0,0 +1,44 @@
+package sqlstore_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/keevferreira/recipes-api/internal/database/migrate"
+	"github.com/keevferreira/recipes-api/internal/database/migrations"
+	"github.com/keevferreira/recipes-api/internal/database/repotest"
+	"github.com/keevferreira/recipes-api/internal/database/sqlstore"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// TestPostgresRepositories runs against the database at DATABASE_URL, which
+// it empties before each test, and is skipped when the variable is unset.
+func TestPostgresRepositories(t *testing.T) {
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	db, err := sqlstore.ConnectToPostgresDB(url)
+	if err != nil {
+		t.Fatalf("ConnectToPostgresDB: %v", err)
+	}
+	t.Cleanup(func() { sqlstore.DisconnectPostgresDB(db) })
+
+	migrator, err := migrate.New(db, migrate.Postgres, migrations.Files)
+	if err != nil {
+		t.Fatalf("migrate.New: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	repotest.Run(t, func(t *testing.T) *models.Repositories {
+		_, err := db.Exec(`TRUNCATE recipe, category, ingredient, shoppinglists, pantryitems, apikeys, users RESTART IDENTITY CASCADE`)
+		if err != nil {
+			t.Fatalf("TRUNCATE: %v", err)
+		}
+
+		return sqlstore.NewRepositories(db, sqlstore.Postgres)
+	})
+}
@@ -0,0 +1,243 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// RecipeRepository is the SQL implementation of models.RecipeRepository.
+type RecipeRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewRecipeRepository creates a RecipeRepository backed by db.
+func NewRecipeRepository(db *sql.DB, dialect Dialect) *RecipeRepository {
+	return &RecipeRepository{db: db, dialect: dialect}
+}
+
+// GetRecipeByID retrieves a recipe by its ID from the database.
+func (rr *RecipeRepository) GetRecipeByID(ctx context.Context, id int) (models.Recipe, error) {
+	var recipe models.Recipe
+
+	err := rr.db.QueryRowContext(ctx, "SELECT id, title, description, preptime, COALESCE(servings, 0), difficulty, COALESCE(authorid, 0), createdat, updatedat FROM recipe WHERE id = $1", id).
+		Scan(&recipe.ID, &recipe.Title, &recipe.Description, &recipe.PrepTime, &recipe.Servings, &recipe.Difficulty, &recipe.AuthorID, &recipe.CreatedAt, &recipe.UpdatedAt)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return models.Recipe{}, models.NewNotFoundError("recipe", id)
+	case err != nil:
+		return models.Recipe{}, err
+	}
+
+	// Fetch ingredients and categories from the database
+	recipe.Ingredients, err = getIngredientsByRecipeID(ctx, rr.db, rr.dialect, id)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	recipe.Categories, err = getCategoriesByRecipeID(ctx, rr.db, rr.dialect, id)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	recipe.Steps, err = getStepsByRecipeID(ctx, rr.db, id)
+	if err != nil {
+		return models.Recipe{}, err
+	}
+
+	return recipe, nil
+}
+
+// UpdateRecipeByID updates a recipe by its ID in the database.
+func (rr *RecipeRepository) UpdateRecipeByID(ctx context.Context, id int, updatedRecipe models.Recipe) error {
+	err := withTx(ctx, rr.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE recipe SET title=$1, description=$2, preptime=$3, servings=$4, difficulty=$5, updatedat=$6 WHERE id=$7",
+			updatedRecipe.Title, updatedRecipe.Description, updatedRecipe.PrepTime, updatedRecipe.Servings, updatedRecipe.Difficulty, time.Now(), id)
+		if err != nil {
+			return err
+		}
+		if err := notFoundIfNone(result, "recipe", id); err != nil {
+			return err
+		}
+
+		err = replaceIngredientsByRecipeID(ctx, tx, id, updatedRecipe.Ingredients)
+		if err != nil {
+			return err
+		}
+
+		err = replaceCategoriesByRecipeID(ctx, tx, id, updatedRecipe.Categories)
+		if err != nil {
+			return err
+		}
+
+		return updateStepsByRecipeID(ctx, tx, id, updatedRecipe.Steps)
+	})
+
+	return referenceError(rr.dialect, err)
+}
+
+// DeleteRecipeByID deletes a recipe by its ID from the database.
+func (rr *RecipeRepository) DeleteRecipeByID(ctx context.Context, id int) error {
+	return withTx(ctx, rr.db, func(tx *sql.Tx) error {
+		// The links reference the recipe, so they must go first
+		err := deleteIngredientsByRecipeID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		err = deleteCategoriesByRecipeID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		err = deleteStepsByRecipeID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM recipe WHERE id=$1", id)
+		if err != nil {
+			return err
+		}
+
+		return notFoundIfNone(result, "recipe", id)
+	})
+}
+
+// recipeSortColumns maps the sort keys of recipes to their columns.
+var recipeSortColumns = map[string]string{
+	models.SortByID:        "id",
+	models.SortByTitle:     "title",
+	models.SortByPrepTime:  "preptime",
+	models.SortByCreatedAt: "createdat",
+}
+
+// GetAllRecipes retrieves a page of the recipes matching filter from the database.
+func (rr *RecipeRepository) GetAllRecipes(ctx context.Context, filter models.RecipeFilter, includes models.RecipeIncludes, options models.ListOptions) ([]models.Recipe, models.PageInfo, error) {
+	var recipes []models.Recipe
+
+	query := listQuery{table: "recipe"}
+	if filter.Difficulty != "" {
+		query.filter("difficulty = " + query.arg(filter.Difficulty))
+	}
+	if filter.MaxPrepTime > 0 {
+		query.filter("preptime <= " + query.arg(filter.MaxPrepTime))
+	}
+	if filter.CategoryID > 0 {
+		query.filter("EXISTS (SELECT 1 FROM recipecategories rc WHERE rc.recipeid = recipe.id AND rc.categoryid = " + query.arg(filter.CategoryID) + ")")
+	}
+	if filter.IngredientID > 0 {
+		query.filter("EXISTS (SELECT 1 FROM recipeingredients ri WHERE ri.recipeid = recipe.id AND ri.ingredientid = " + query.arg(filter.IngredientID) + ")")
+	}
+	if filter.AuthorID > 0 {
+		query.filter("authorid = " + query.arg(filter.AuthorID))
+	}
+
+	total, err := query.count(ctx, rr.db)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	rows, err := rr.db.QueryContext(ctx, "SELECT id, title, description, preptime, COALESCE(servings, 0), difficulty, COALESCE(authorid, 0), createdat, updatedat FROM recipe"+
+		query.page(recipeSortColumns[options.Sort], options), query.args...)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipe models.Recipe
+		err := rows.Scan(&recipe.ID, &recipe.Title, &recipe.Description, &recipe.PrepTime, &recipe.Servings, &recipe.Difficulty, &recipe.AuthorID, &recipe.CreatedAt, &recipe.UpdatedAt)
+		if err != nil {
+			return nil, models.PageInfo{}, err
+		}
+
+		recipes = append(recipes, recipe)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	recipes, info := models.Page(options, recipes, total)
+
+	err = loadRecipeIncludes(ctx, rr.db, rr.dialect, recipes, includes)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	return recipes, info, nil
+}
+
+// loadRecipeIncludes fills in the associations of recipes selected by
+// includes, with one query per association.
+func loadRecipeIncludes(ctx context.Context, q queryer, d Dialect, recipes []models.Recipe, includes models.RecipeIncludes) error {
+	ids := make([]int, 0, len(recipes))
+	for _, recipe := range recipes {
+		ids = append(ids, recipe.ID)
+	}
+
+	if includes.Ingredients {
+		ingredients, err := getIngredientsByRecipeIDs(ctx, q, d, ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range recipes {
+			recipes[i].Ingredients = ingredients[recipes[i].ID]
+		}
+	}
+
+	if includes.Categories {
+		categories, err := getCategoriesByRecipeIDs(ctx, q, d, ids)
+		if err != nil {
+			return err
+		}
+
+		for i := range recipes {
+			recipes[i].Categories = categories[recipes[i].ID]
+		}
+	}
+
+	return nil
+}
+
+// CreateRecipe creates a new recipe in the database.
+func (rr *RecipeRepository) CreateRecipe(ctx context.Context, recipe models.Recipe) (int, error) {
+	var id int
+
+	err := withTx(ctx, rr.db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, "INSERT INTO recipe (title, description, preptime, servings, difficulty, authorid, createdat, updatedat) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8) RETURNING id",
+			recipe.Title, recipe.Description, recipe.PrepTime, recipe.Servings, recipe.Difficulty, recipe.AuthorID, time.Now(), time.Now()).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		// Insert ingredients, associate categories and add the steps of the recipe
+		err = insertIngredientsByRecipeID(ctx, tx, id, recipe.Ingredients)
+		if err != nil {
+			return err
+		}
+
+		err = insertCategoriesByRecipeID(ctx, tx, id, recipe.Categories)
+		if err != nil {
+			return err
+		}
+
+		return insertStepsByRecipeID(ctx, tx, id, recipe.Steps)
+	})
+	if err != nil {
+		return 0, referenceError(rr.dialect, err)
+	}
+
+	return id, nil
+}
+
+// SearchRecipes runs a full-text search on the recipes, as the dialect does it.
+func (rr *RecipeRepository) SearchRecipes(ctx context.Context, query string, options models.ListOptions) ([]models.RecipeSearchResult, models.PageInfo, error) {
+	return rr.dialect.searchRecipes(ctx, rr.db, query, options)
+}
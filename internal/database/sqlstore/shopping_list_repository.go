@@ -0,0 +1,126 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// ShoppingListRepository is the SQL implementation of models.ShoppingListRepository.
+type ShoppingListRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewShoppingListRepository creates a ShoppingListRepository backed by db.
+func NewShoppingListRepository(db *sql.DB, dialect Dialect) *ShoppingListRepository {
+	return &ShoppingListRepository{db: db, dialect: dialect}
+}
+
+// GetShoppingListByID retrieves a shopping list with its items from the database.
+func (sr *ShoppingListRepository) GetShoppingListByID(ctx context.Context, id int) (models.ShoppingList, error) {
+	var list models.ShoppingList
+
+	err := sr.db.QueryRowContext(ctx, "SELECT id, name, COALESCE(owner, ''), createdat, updatedat FROM shoppinglists WHERE id = $1", id).
+		Scan(&list.ID, &list.Name, &list.Owner, &list.CreatedAt, &list.UpdatedAt)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return models.ShoppingList{}, models.NewNotFoundError("shopping list", id)
+	case err != nil:
+		return models.ShoppingList{}, err
+	}
+
+	query := `
+		SELECT sli.id, sli.ingredientid, i.name, COALESCE(sli.aisle, ''), sli.quantity, COALESCE(sli.unit, ''), sli.checked
+		FROM shoppinglistitems sli
+		INNER JOIN ingredient i ON i.id = sli.ingredientid
+		WHERE sli.shoppinglistid = $1
+		ORDER BY sli.id
+	`
+
+	rows, err := sr.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return models.ShoppingList{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ShoppingListItem
+		err := rows.Scan(&item.ID, &item.IngredientID, &item.Name, &item.Aisle, &item.Quantity, &item.Unit, &item.Checked)
+		if err != nil {
+			return models.ShoppingList{}, err
+		}
+
+		list.Items = append(list.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.ShoppingList{}, err
+	}
+
+	return list, nil
+}
+
+// CreateShoppingList creates a shopping list with its items in the database.
+func (sr *ShoppingListRepository) CreateShoppingList(ctx context.Context, list models.ShoppingList) (int, error) {
+	var id int
+
+	err := withTx(ctx, sr.db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, "INSERT INTO shoppinglists (name, owner, createdat, updatedat) VALUES ($1, $2, $3, $4) RETURNING id",
+			list.Name, list.Owner, time.Now(), time.Now()).Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.PrepareContext(ctx, "INSERT INTO shoppinglistitems (shoppinglistid, ingredientid, aisle, quantity, unit, checked, createdat, updatedat) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, item := range list.Items {
+			_, err := stmt.ExecContext(ctx, id, item.IngredientID, item.Aisle, item.Quantity, item.Unit, item.Checked, time.Now(), time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, referenceError(sr.dialect, err)
+	}
+
+	return id, nil
+}
+
+// DeleteShoppingListByID deletes a shopping list and its items from the database.
+func (sr *ShoppingListRepository) DeleteShoppingListByID(ctx context.Context, id int) error {
+	return withTx(ctx, sr.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM shoppinglistitems WHERE shoppinglistid = $1", id)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM shoppinglists WHERE id = $1", id)
+		if err != nil {
+			return err
+		}
+
+		return notFoundIfNone(result, "shopping list", id)
+	})
+}
+
+// CheckShoppingListItem marks an item of a shopping list as bought or not.
+func (sr *ShoppingListRepository) CheckShoppingListItem(ctx context.Context, listID int, itemID int, checked bool) error {
+	result, err := sr.db.ExecContext(ctx, "UPDATE shoppinglistitems SET checked=$1, updatedat=$2 WHERE id=$3 AND shoppinglistid=$4",
+		checked, time.Now(), itemID, listID)
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(result, "shopping list item", itemID)
+}
@@ -0,0 +1,69 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ConnectToSQLiteDB opens the SQLite database file at path, creating it if needed.
+func ConnectToSQLiteDB(path string) (*sql.DB, error) {
+	db, err := tracing.OpenDB("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", semconv.DBSystemSqlite)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir o banco de dados SQLite: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection also keeps ":memory:"
+	// databases from being split across connections.
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("falha ao abrir o banco de dados SQLite: %w", err)
+	}
+
+	slog.Info("conexão com o banco de dados estabelecida", slog.String("driver", "sqlite"))
+	return db, nil
+}
+
+// SQLite is the dialect of SQLite.
+var SQLite Dialect = sqliteDialect{}
+
+type sqliteDialect struct{}
+
+// inIDs matches column against a list with one placeholder per ID, since
+// SQLite has no arrays.
+func (sqliteDialect) inIDs(column string, ids []int) (string, []any) {
+	placeholders := make([]string, 0, len(ids))
+	args := make([]any, 0, len(ids))
+	for i, id := range ids {
+		placeholders = append(placeholders, "$"+strconv.Itoa(i+1))
+		args = append(args, id)
+	}
+
+	return column + " IN (" + strings.Join(placeholders, ", ") + ")", args
+}
+
+func (sqliteDialect) violation(err error) constraint {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return noConstraint
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return foreignKeyConstraint
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		return uniqueConstraint
+	}
+
+	return noConstraint
+}
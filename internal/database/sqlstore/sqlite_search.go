@@ -0,0 +1,85 @@
+package sqlstore
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/logging"
+	"github.com/keevferreira/recipes-api/internal/models"
+	"github.com/keevferreira/recipes-api/internal/search"
+)
+
+// searchRecipes searches the recipes through the RecipeSearch full-text
+// table, kept up to date by the triggers of the 013 migration. Each query
+// term is stemmed and matched as a prefix, which stands in for the
+// Portuguese stemming SQLite lacks.
+func (sqliteDialect) searchRecipes(ctx context.Context, db queryer, query string, options models.ListOptions) ([]models.RecipeSearchResult, models.PageInfo, error) {
+	match := matchExpression(search.Parse(query))
+	if match == "" {
+		return nil, models.PageInfo{}, nil
+	}
+
+	var total int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM recipesearch WHERE recipesearch MATCH $1", match).Scan(&total)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	// bm25 is lower for better matches; the weights follow the columns
+	// title, description, ingredients and steps. The snippet is delimited
+	// by marks and escaped afterwards, as snippet() copies the text as is.
+	rows, err := db.QueryContext(ctx, `
+		SELECT rowid, title, -bm25(recipesearch, 10.0, 2.0, 4.0, 1.0),
+			snippet(recipesearch, -1, $1, $2, '…', 16)
+		FROM recipesearch
+		WHERE recipesearch MATCH $3
+		ORDER BY 3 DESC, rowid
+		LIMIT $4 OFFSET $5
+	`, search.StartMark, search.StopMark, match, options.Limit+1, options.Offset)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var results []models.RecipeSearchResult
+	for rows.Next() {
+		var result models.RecipeSearchResult
+		if err := rows.Scan(&result.RecipeID, &result.Title, &result.Rank, &result.Snippet); err != nil {
+			return nil, models.PageInfo{}, err
+		}
+		result.Snippet = search.Highlight(result.Snippet)
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	results, info := models.Page(options, results, total)
+	logging.FromContext(ctx).Debug("recipe search", slog.String("query", query), slog.Int("total", total))
+
+	return results, info, nil
+}
+
+// matchExpression builds an FTS5 query requiring every term of query as a
+// prefix and none of the excluded ones. Terms only hold letters and digits,
+// so quoting them is enough.
+func matchExpression(query search.Query) string {
+	if len(query.Terms) == 0 {
+		return ""
+	}
+
+	phrases := make([]string, 0, len(query.Terms))
+	for _, term := range query.Terms {
+		phrases = append(phrases, `"`+term+`"*`)
+	}
+
+	match := "(" + strings.Join(phrases, " ") + ")"
+	for _, term := range query.Excluded {
+		match += ` NOT "` + term + `"*`
+	}
+
+	return match
+}
@@ -0,0 +1,32 @@
+package sqlstore_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/keevferreira/recipes-api/internal/database/migrate"
+	"github.com/keevferreira/recipes-api/internal/database/migrations"
+	"github.com/keevferreira/recipes-api/internal/database/repotest"
+	"github.com/keevferreira/recipes-api/internal/database/sqlstore"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+func TestSQLiteRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *models.Repositories {
+		db, err := sqlstore.ConnectToSQLiteDB(filepath.Join(t.TempDir(), "recipes.db"))
+		if err != nil {
+			t.Fatalf("ConnectToSQLiteDB: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		migrator, err := migrate.New(db, migrate.SQLite, migrations.SQLite())
+		if err != nil {
+			t.Fatalf("migrate.New: %v", err)
+		}
+		if _, err := migrator.Up(); err != nil {
+			t.Fatalf("Up: %v", err)
+		}
+
+		return sqlstore.NewRepositories(db, sqlstore.SQLite)
+	})
+}
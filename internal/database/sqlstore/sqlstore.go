@@ -0,0 +1,74 @@
+// Package sqlstore is the database/sql implementation of the repositories,
+// shared by PostgreSQL and SQLite. The queries use $N placeholders, which
+// both drivers accept; what really differs between the two databases is kept
+// behind a Dialect.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/keevferreira/recipes-api/internal/logging"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// queryer is implemented by both *sql.DB and *sql.Tx, so the helpers below
+// can run either standalone or as part of a larger transaction.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// constraint is a kind of constraint a statement can break.
+type constraint int
+
+const (
+	noConstraint constraint = iota
+	foreignKeyConstraint
+	uniqueConstraint
+)
+
+// Dialect holds what differs between the databases the repositories run on.
+// Postgres and SQLite are its implementations.
+type Dialect interface {
+	// inIDs returns a condition matching column against ids, to be used as
+	// the only placeholders of a query, along with its arguments.
+	inIDs(column string, ids []int) (string, []any)
+	// violation returns the kind of constraint err broke, or noConstraint
+	// when err is not a constraint failure.
+	violation(err error) constraint
+	// searchRecipes runs the full-text search of RecipeRepository.SearchRecipes.
+	searchRecipes(ctx context.Context, db queryer, query string, options models.ListOptions) ([]models.RecipeSearchResult, models.PageInfo, error)
+}
+
+// NewRepositories creates every repository on db, which speaks dialect.
+func NewRepositories(db *sql.DB, dialect Dialect) *models.Repositories {
+	return &models.Repositories{
+		Recipes:       NewRecipeRepository(db, dialect),
+		Ingredients:   NewIngredientRepository(db, dialect),
+		Categories:    NewCategoryRepository(db, dialect),
+		ShoppingLists: NewShoppingListRepository(db, dialect),
+		Pantry:        NewPantryRepository(db, dialect),
+		APIKeys:       NewAPIKeyRepository(db, dialect),
+		Users:         NewUserRepository(db, dialect),
+	}
+}
+
+// withTx runs fn inside a transaction, committing on success and rolling back on error.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			logging.FromContext(ctx).Warn("transaction rollback failed", slog.String("error", rollbackErr.Error()))
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
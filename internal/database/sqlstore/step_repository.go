@@ -0,0 +1,275 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// GetStepsByRecipeID retrieves the steps of a recipe ordered by position.
+func (rr *RecipeRepository) GetStepsByRecipeID(ctx context.Context, recipeID int) ([]models.Step, error) {
+	return getStepsByRecipeID(ctx, rr.db, recipeID)
+}
+
+// ReorderStepsByRecipeID rewrites the position of every step of a recipe to follow stepIDs.
+func (rr *RecipeRepository) ReorderStepsByRecipeID(ctx context.Context, recipeID int, stepIDs []int) error {
+	return withTx(ctx, rr.db, func(tx *sql.Tx) error {
+		if err := checkExists(ctx, tx, "recipe", "recipe", recipeID); err != nil {
+			return err
+		}
+
+		current, err := stepIDsByRecipeID(ctx, tx, recipeID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkStepOrder(recipeID, current, stepIDs); err != nil {
+			return err
+		}
+
+		for i, stepID := range stepIDs {
+			_, err := tx.ExecContext(ctx, "UPDATE recipesteps SET position=$1, updatedat=$2 WHERE id=$3", i+1, time.Now(), stepID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// getStepsByRecipeID retrieves the steps of a recipe with the ingredients each one uses.
+func getStepsByRecipeID(ctx context.Context, q queryer, recipeID int) ([]models.Step, error) {
+	var steps []models.Step
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, position, text, duration, temperature, COALESCE(temperatureunit, '')
+		FROM recipesteps
+		WHERE recipeid = $1
+		ORDER BY position
+	`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var step models.Step
+		var duration sql.NullInt64
+		var temperature sql.NullFloat64
+		err := rows.Scan(&step.ID, &step.Position, &step.Text, &duration, &temperature, &step.TemperatureUnit)
+		if err != nil {
+			return nil, err
+		}
+
+		if duration.Valid {
+			d := int(duration.Int64)
+			step.Duration = &d
+		}
+		if temperature.Valid {
+			t := temperature.Float64
+			step.Temperature = &t
+		}
+
+		steps = append(steps, step)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(steps) == 0 {
+		return steps, nil
+	}
+
+	ingredientRows, err := q.QueryContext(ctx, `
+		SELECT rsi.stepid, rsi.ingredientid
+		FROM recipestepingredients rsi
+		INNER JOIN recipesteps rs ON rs.id = rsi.stepid
+		WHERE rs.recipeid = $1
+		ORDER BY rsi.id
+	`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer ingredientRows.Close()
+
+	byStepID := make(map[int]*models.Step, len(steps))
+	for i := range steps {
+		byStepID[steps[i].ID] = &steps[i]
+	}
+
+	for ingredientRows.Next() {
+		var stepID, ingredientID int
+		if err := ingredientRows.Scan(&stepID, &ingredientID); err != nil {
+			return nil, err
+		}
+		if step, ok := byStepID[stepID]; ok {
+			step.IngredientIDs = append(step.IngredientIDs, ingredientID)
+		}
+	}
+
+	if err := ingredientRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+// stepIDsByRecipeID retrieves the IDs of the current steps of a recipe.
+func stepIDsByRecipeID(ctx context.Context, q queryer, recipeID int) (map[int]bool, error) {
+	rows, err := q.QueryContext(ctx, "SELECT id FROM recipesteps WHERE recipeid = $1", recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	current := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		current[id] = true
+	}
+
+	return current, rows.Err()
+}
+
+// updateStepsByRecipeID makes the steps of a recipe match the given ones, positioned
+// in slice order. Steps with the ID of a current step are updated in place, so their
+// IDs stay valid for the clients that read them; the others are inserted, and the
+// current steps left out are deleted.
+func updateStepsByRecipeID(ctx context.Context, tx *sql.Tx, recipeID int, steps []models.Step) error {
+	current, err := stepIDsByRecipeID(ctx, tx, recipeID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkStepIDs(recipeID, current, steps); err != nil {
+		return err
+	}
+
+	for _, step := range steps {
+		delete(current, step.ID)
+	}
+	for stepID := range current {
+		_, err := tx.ExecContext(ctx, "DELETE FROM recipesteps WHERE id = $1", stepID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, step := range steps {
+		if step.ID == 0 {
+			if err := insertStep(ctx, tx, recipeID, i+1, step); err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := tx.ExecContext(ctx, "UPDATE recipesteps SET position=$1, text=$2, duration=$3, temperature=$4, temperatureunit=$5, updatedat=$6 WHERE id=$7",
+			i+1, step.Text, step.Duration, step.Temperature, step.TemperatureUnit, time.Now(), step.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM recipestepingredients WHERE stepid = $1", step.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := insertStepIngredients(ctx, tx, step.ID, step.IngredientIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertStepsByRecipeID inserts the steps of a recipe, positioned in slice order.
+func insertStepsByRecipeID(ctx context.Context, tx *sql.Tx, recipeID int, steps []models.Step) error {
+	for i, step := range steps {
+		if err := insertStep(ctx, tx, recipeID, i+1, step); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertStep inserts a step of a recipe at position, with the ingredients it uses.
+func insertStep(ctx context.Context, tx *sql.Tx, recipeID int, position int, step models.Step) error {
+	var stepID int
+	err := tx.QueryRowContext(ctx, "INSERT INTO recipesteps (recipeid, position, text, duration, temperature, temperatureunit, createdat, updatedat) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
+		recipeID, position, step.Text, step.Duration, step.Temperature, step.TemperatureUnit, time.Now(), time.Now()).Scan(&stepID)
+	if err != nil {
+		return err
+	}
+
+	return insertStepIngredients(ctx, tx, stepID, step.IngredientIDs)
+}
+
+// insertStepIngredients links a step to the ingredients it uses.
+func insertStepIngredients(ctx context.Context, tx *sql.Tx, stepID int, ingredientIDs []int) error {
+	for _, ingredientID := range ingredientIDs {
+		_, err := tx.ExecContext(ctx, "INSERT INTO recipestepingredients (stepid, ingredientid) VALUES ($1, $2)", stepID, ingredientID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteStepsByRecipeID removes every step of a recipe. The step ingredients go with them.
+func deleteStepsByRecipeID(ctx context.Context, q queryer, recipeID int) error {
+	_, err := q.ExecContext(ctx, "DELETE FROM recipesteps WHERE recipeid = $1", recipeID)
+	return err
+}
+
+// checkStepOrder reports an error unless stepIDs lists every current step exactly once.
+func checkStepOrder(recipeID int, current map[int]bool, stepIDs []int) error {
+	if len(stepIDs) != len(current) {
+		return models.NewFieldError("step_ids", models.FieldInvalid, "recipe with ID %d has %d steps, got %d in the new order", recipeID, len(current), len(stepIDs))
+	}
+
+	seen := make(map[int]bool, len(stepIDs))
+	for _, stepID := range stepIDs {
+		if !current[stepID] {
+			return models.NewFieldError("step_ids", models.FieldInvalid, "step with ID %d does not belong to recipe with ID %d", stepID, recipeID)
+		}
+		if seen[stepID] {
+			return models.NewFieldError("step_ids", models.FieldInvalid, "step with ID %d is listed more than once", stepID)
+		}
+		seen[stepID] = true
+	}
+
+	return nil
+}
+
+// checkStepIDs reports an error if a step has the ID of a step that is not one of the
+// current steps of the recipe, or if two steps have the same ID. Steps without an ID
+// are new.
+func checkStepIDs(recipeID int, current map[int]bool, steps []models.Step) error {
+	seen := make(map[int]bool, len(steps))
+	for i, step := range steps {
+		if step.ID == 0 {
+			continue
+		}
+
+		field := fmt.Sprintf("steps[%d].id", i)
+		if !current[step.ID] {
+			return models.NewFieldError(field, models.FieldInvalid, "step with ID %d does not belong to recipe with ID %d", step.ID, recipeID)
+		}
+		if seen[step.ID] {
+			return models.NewFieldError(field, models.FieldInvalid, "step with ID %d is listed more than once", step.ID)
+		}
+		seen[step.ID] = true
+	}
+
+	return nil
+}
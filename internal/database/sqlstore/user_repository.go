@@ -0,0 +1,77 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// UserRepository is the SQL implementation of models.UserRepository.
+type UserRepository struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewUserRepository creates a UserRepository backed by db.
+func NewUserRepository(db *sql.DB, dialect Dialect) *UserRepository {
+	return &UserRepository{db: db, dialect: dialect}
+}
+
+// GetUserByID retrieves a user by its ID from the database.
+func (ur *UserRepository) GetUserByID(ctx context.Context, id int) (models.User, error) {
+	user, err := ur.getUser(ctx, "id = $1", id)
+	if err == sql.ErrNoRows {
+		return models.User{}, models.NewNotFoundError("user", id)
+	}
+
+	return user, err
+}
+
+// GetUserByEmail retrieves a user by its e-mail from the database.
+func (ur *UserRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	user, err := ur.getUser(ctx, "email = $1", email)
+	if err == sql.ErrNoRows {
+		return models.User{}, fmt.Errorf("user %w", models.ErrNotFound)
+	}
+
+	return user, err
+}
+
+// getUser retrieves the user matching condition, which has a single placeholder.
+func (ur *UserRepository) getUser(ctx context.Context, condition string, arg any) (models.User, error) {
+	var user models.User
+
+	err := ur.db.QueryRowContext(ctx, "SELECT id, email, name, passwordhash, role, createdat, updatedat FROM users WHERE "+condition, arg).
+		Scan(&user.ID, &user.Email, &user.Name, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// CreateUser creates a new user in the database.
+func (ur *UserRepository) CreateUser(ctx context.Context, user models.User) (int, error) {
+	var id int
+
+	err := ur.db.QueryRowContext(ctx, "INSERT INTO users (email, name, passwordhash, role, createdat, updatedat) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		user.Email, user.Name, user.PasswordHash, user.Role, time.Now(), time.Now()).Scan(&id)
+	if err != nil {
+		return 0, duplicateError(ur.dialect, err, "a user with e-mail %s already exists", user.Email)
+	}
+
+	return id, nil
+}
+
+// SetUserRole changes the role of a user in the database.
+func (ur *UserRepository) SetUserRole(ctx context.Context, id int, role string) error {
+	result, err := ur.db.ExecContext(ctx, "UPDATE users SET role = $1, updatedat = $2 WHERE id = $3", role, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(result, "user", id)
+}
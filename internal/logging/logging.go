@@ -0,0 +1,58 @@
+// Package logging sets up the structured JSON logs of the service and carries
+// the ID of the current request in its context, so that the handlers and the
+// repositories log lines that can be traced back to one request, and to its
+// trace when the request is traced.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New creates a logger writing JSON lines to w at level and above.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// ParseLevel parses "debug", "info", "warn" or "error".
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToUpper(name))); err != nil {
+		return 0, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", name)
+	}
+
+	return level, nil
+}
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the default logger, with the request ID and the trace
+// and span IDs of ctx attached to every line when there are some.
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if id := RequestID(ctx); id != "" {
+		logger = logger.With(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		logger = logger.With(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+
+	return logger
+}
@@ -0,0 +1,90 @@
+// Package metrics exposes the Prometheus metrics of the API: HTTP requests,
+// repository calls, the database connection pool and business counters.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the names of every metric of the API.
+const namespace = "recipes"
+
+// Registry holds the metrics of the API. A registry of its own keeps the
+// output free of the collectors other packages register on the default one.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// RequestsTotal counts the HTTP requests served, by route template and status.
+	RequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by method, mux route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	// RequestDuration observes the latency of the HTTP requests served.
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of the HTTP requests, by method, mux route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// QueryDuration observes the duration of the repository methods, which
+	// is the time spent in the database for the SQL backends.
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of the repository calls, by repository, method and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repository", "method", "outcome"})
+
+	// RecipesCreated counts the recipes created.
+	RecipesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recipes_created_total",
+		Help:      "Recipes created.",
+	})
+
+	// RateLimited counts the requests refused for exceeding a rate limit.
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_rate_limited_total",
+		Help:      "HTTP requests refused with 429, by limit: a class of requests or a route with a limit of its own.",
+	}, []string{"limit"})
+
+	// SearchesRun counts the recipe searches run.
+	SearchesRun = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recipe_searches_total",
+		Help:      "Recipe searches run.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RequestsTotal,
+		RequestDuration,
+		QueryDuration,
+		RateLimited,
+		RecipesCreated,
+		SearchesRun,
+	)
+}
+
+// RegisterDB exposes the statistics of the connection pool of db, as
+// reported by sql.DB.Stats, as the go_sql_* metrics labelled db_name="recipes".
+func RegisterDB(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,368 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// InstrumentRepositories wraps every repository so that the duration of each
+// of its methods is observed in QueryDuration and the business counters are
+// kept up to date. It works the same over every storage backend.
+func InstrumentRepositories(repositories *models.Repositories) *models.Repositories {
+	return &models.Repositories{
+		Recipes:       &recipeRepository{next: repositories.Recipes},
+		Ingredients:   &ingredientRepository{next: repositories.Ingredients},
+		Categories:    &categoryRepository{next: repositories.Categories},
+		ShoppingLists: &shoppingListRepository{next: repositories.ShoppingLists},
+		Pantry:        &pantryRepository{next: repositories.Pantry},
+		APIKeys:       &apiKeyRepository{next: repositories.APIKeys},
+		Users:         &userRepository{next: repositories.Users},
+	}
+}
+
+// observe records the duration of a repository method that started at start.
+func observe(repository string, method string, start time.Time, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	QueryDuration.WithLabelValues(repository, method, outcome).Observe(time.Since(start).Seconds())
+}
+
+// recipeRepository instruments a models.RecipeRepository.
+type recipeRepository struct {
+	next models.RecipeRepository
+}
+
+func (r *recipeRepository) GetRecipeByID(ctx context.Context, id int) (models.Recipe, error) {
+	start := time.Now()
+	result, err := r.next.GetRecipeByID(ctx, id)
+	observe("recipes", "GetRecipeByID", start, err)
+	return result, err
+}
+
+func (r *recipeRepository) GetAllRecipes(ctx context.Context, filter models.RecipeFilter, includes models.RecipeIncludes, options models.ListOptions) ([]models.Recipe, models.PageInfo, error) {
+	start := time.Now()
+	items, info, err := r.next.GetAllRecipes(ctx, filter, includes, options)
+	observe("recipes", "GetAllRecipes", start, err)
+	return items, info, err
+}
+
+func (r *recipeRepository) CreateRecipe(ctx context.Context, recipe models.Recipe) (int, error) {
+	start := time.Now()
+	result, err := r.next.CreateRecipe(ctx, recipe)
+	observe("recipes", "CreateRecipe", start, err)
+	if err == nil {
+		RecipesCreated.Inc()
+	}
+	return result, err
+}
+
+func (r *recipeRepository) UpdateRecipeByID(ctx context.Context, id int, updatedRecipe models.Recipe) error {
+	start := time.Now()
+	err := r.next.UpdateRecipeByID(ctx, id, updatedRecipe)
+	observe("recipes", "UpdateRecipeByID", start, err)
+	return err
+}
+
+func (r *recipeRepository) DeleteRecipeByID(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeleteRecipeByID(ctx, id)
+	observe("recipes", "DeleteRecipeByID", start, err)
+	return err
+}
+
+func (r *recipeRepository) SearchRecipes(ctx context.Context, query string, options models.ListOptions) ([]models.RecipeSearchResult, models.PageInfo, error) {
+	start := time.Now()
+	items, info, err := r.next.SearchRecipes(ctx, query, options)
+	observe("recipes", "SearchRecipes", start, err)
+	if err == nil {
+		SearchesRun.Inc()
+	}
+	return items, info, err
+}
+
+func (r *recipeRepository) GetStepsByRecipeID(ctx context.Context, recipeID int) ([]models.Step, error) {
+	start := time.Now()
+	result, err := r.next.GetStepsByRecipeID(ctx, recipeID)
+	observe("recipes", "GetStepsByRecipeID", start, err)
+	return result, err
+}
+
+func (r *recipeRepository) ReorderStepsByRecipeID(ctx context.Context, recipeID int, stepIDs []int) error {
+	start := time.Now()
+	err := r.next.ReorderStepsByRecipeID(ctx, recipeID, stepIDs)
+	observe("recipes", "ReorderStepsByRecipeID", start, err)
+	return err
+}
+
+// ingredientRepository instruments a models.IngredientRepository.
+type ingredientRepository struct {
+	next models.IngredientRepository
+}
+
+func (r *ingredientRepository) GetIngredientByID(ctx context.Context, id int) (models.Ingredient, error) {
+	start := time.Now()
+	result, err := r.next.GetIngredientByID(ctx, id)
+	observe("ingredients", "GetIngredientByID", start, err)
+	return result, err
+}
+
+func (r *ingredientRepository) GetIngredientsByIDs(ctx context.Context, ids []int) ([]models.Ingredient, error) {
+	start := time.Now()
+	result, err := r.next.GetIngredientsByIDs(ctx, ids)
+	observe("ingredients", "GetIngredientsByIDs", start, err)
+	return result, err
+}
+
+func (r *ingredientRepository) GetAllIngredients(ctx context.Context, filter models.IngredientFilter, options models.ListOptions) ([]models.Ingredient, models.PageInfo, error) {
+	start := time.Now()
+	items, info, err := r.next.GetAllIngredients(ctx, filter, options)
+	observe("ingredients", "GetAllIngredients", start, err)
+	return items, info, err
+}
+
+func (r *ingredientRepository) CreateIngredient(ctx context.Context, ingredient models.Ingredient) (int, error) {
+	start := time.Now()
+	result, err := r.next.CreateIngredient(ctx, ingredient)
+	observe("ingredients", "CreateIngredient", start, err)
+	return result, err
+}
+
+func (r *ingredientRepository) UpdateIngredientByID(ctx context.Context, id int, updatedIngredient models.Ingredient) error {
+	start := time.Now()
+	err := r.next.UpdateIngredientByID(ctx, id, updatedIngredient)
+	observe("ingredients", "UpdateIngredientByID", start, err)
+	return err
+}
+
+func (r *ingredientRepository) DeleteIngredientByID(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeleteIngredientByID(ctx, id)
+	observe("ingredients", "DeleteIngredientByID", start, err)
+	return err
+}
+
+func (r *ingredientRepository) GetIngredientsByRecipeID(ctx context.Context, recipeID int) ([]models.RecipeIngredient, error) {
+	start := time.Now()
+	result, err := r.next.GetIngredientsByRecipeID(ctx, recipeID)
+	observe("ingredients", "GetIngredientsByRecipeID", start, err)
+	return result, err
+}
+
+func (r *ingredientRepository) UpdateIngredientsByRecipeID(ctx context.Context, recipeID int, updatedIngredients []models.RecipeIngredient) error {
+	start := time.Now()
+	err := r.next.UpdateIngredientsByRecipeID(ctx, recipeID, updatedIngredients)
+	observe("ingredients", "UpdateIngredientsByRecipeID", start, err)
+	return err
+}
+
+func (r *ingredientRepository) DeleteIngredientsByRecipeID(ctx context.Context, recipeID int) error {
+	start := time.Now()
+	err := r.next.DeleteIngredientsByRecipeID(ctx, recipeID)
+	observe("ingredients", "DeleteIngredientsByRecipeID", start, err)
+	return err
+}
+
+// categoryRepository instruments a models.CategoryRepository.
+type categoryRepository struct {
+	next models.CategoryRepository
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, id int) (models.Category, error) {
+	start := time.Now()
+	result, err := r.next.GetCategoryByID(ctx, id)
+	observe("categories", "GetCategoryByID", start, err)
+	return result, err
+}
+
+func (r *categoryRepository) GetCategoriesByIDs(ctx context.Context, ids []int) ([]models.Category, error) {
+	start := time.Now()
+	result, err := r.next.GetCategoriesByIDs(ctx, ids)
+	observe("categories", "GetCategoriesByIDs", start, err)
+	return result, err
+}
+
+func (r *categoryRepository) GetAllCategories(ctx context.Context, options models.ListOptions) ([]models.Category, models.PageInfo, error) {
+	start := time.Now()
+	items, info, err := r.next.GetAllCategories(ctx, options)
+	observe("categories", "GetAllCategories", start, err)
+	return items, info, err
+}
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, category models.Category) (int, error) {
+	start := time.Now()
+	result, err := r.next.CreateCategory(ctx, category)
+	observe("categories", "CreateCategory", start, err)
+	return result, err
+}
+
+func (r *categoryRepository) UpdateCategoryByID(ctx context.Context, id int, updatedCategory models.Category) error {
+	start := time.Now()
+	err := r.next.UpdateCategoryByID(ctx, id, updatedCategory)
+	observe("categories", "UpdateCategoryByID", start, err)
+	return err
+}
+
+func (r *categoryRepository) DeleteCategoryByID(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeleteCategoryByID(ctx, id)
+	observe("categories", "DeleteCategoryByID", start, err)
+	return err
+}
+
+func (r *categoryRepository) GetCategoriesByRecipeID(ctx context.Context, recipeID int) ([]models.Category, error) {
+	start := time.Now()
+	result, err := r.next.GetCategoriesByRecipeID(ctx, recipeID)
+	observe("categories", "GetCategoriesByRecipeID", start, err)
+	return result, err
+}
+
+func (r *categoryRepository) UpdateCategoriesByRecipeID(ctx context.Context, recipeID int, updatedCategories []models.Category) error {
+	start := time.Now()
+	err := r.next.UpdateCategoriesByRecipeID(ctx, recipeID, updatedCategories)
+	observe("categories", "UpdateCategoriesByRecipeID", start, err)
+	return err
+}
+
+func (r *categoryRepository) DeleteCategoryByRecipeID(ctx context.Context, recipeID int) error {
+	start := time.Now()
+	err := r.next.DeleteCategoryByRecipeID(ctx, recipeID)
+	observe("categories", "DeleteCategoryByRecipeID", start, err)
+	return err
+}
+
+// shoppingListRepository instruments a models.ShoppingListRepository.
+type shoppingListRepository struct {
+	next models.ShoppingListRepository
+}
+
+func (r *shoppingListRepository) GetShoppingListByID(ctx context.Context, id int) (models.ShoppingList, error) {
+	start := time.Now()
+	result, err := r.next.GetShoppingListByID(ctx, id)
+	observe("shopping_lists", "GetShoppingListByID", start, err)
+	return result, err
+}
+
+func (r *shoppingListRepository) CreateShoppingList(ctx context.Context, list models.ShoppingList) (int, error) {
+	start := time.Now()
+	result, err := r.next.CreateShoppingList(ctx, list)
+	observe("shopping_lists", "CreateShoppingList", start, err)
+	return result, err
+}
+
+func (r *shoppingListRepository) DeleteShoppingListByID(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeleteShoppingListByID(ctx, id)
+	observe("shopping_lists", "DeleteShoppingListByID", start, err)
+	return err
+}
+
+func (r *shoppingListRepository) CheckShoppingListItem(ctx context.Context, listID int, itemID int, checked bool) error {
+	start := time.Now()
+	err := r.next.CheckShoppingListItem(ctx, listID, itemID, checked)
+	observe("shopping_lists", "CheckShoppingListItem", start, err)
+	return err
+}
+
+// pantryRepository instruments a models.PantryRepository.
+type pantryRepository struct {
+	next models.PantryRepository
+}
+
+func (r *pantryRepository) GetPantryItems(ctx context.Context, owner string) ([]models.PantryItem, error) {
+	start := time.Now()
+	result, err := r.next.GetPantryItems(ctx, owner)
+	observe("pantry", "GetPantryItems", start, err)
+	return result, err
+}
+
+func (r *pantryRepository) SetPantryItem(ctx context.Context, owner string, item models.PantryItem) error {
+	start := time.Now()
+	err := r.next.SetPantryItem(ctx, owner, item)
+	observe("pantry", "SetPantryItem", start, err)
+	return err
+}
+
+func (r *pantryRepository) DeletePantryItem(ctx context.Context, owner string, ingredientID int) error {
+	start := time.Now()
+	err := r.next.DeletePantryItem(ctx, owner, ingredientID)
+	observe("pantry", "DeletePantryItem", start, err)
+	return err
+}
+
+func (r *pantryRepository) FindCookableRecipes(ctx context.Context, owner string, maxMissing int, limit int) ([]models.CookableRecipe, error) {
+	start := time.Now()
+	result, err := r.next.FindCookableRecipes(ctx, owner, maxMissing, limit)
+	observe("pantry", "FindCookableRecipes", start, err)
+	return result, err
+}
+
+// apiKeyRepository instruments a models.APIKeyRepository.
+type apiKeyRepository struct {
+	next models.APIKeyRepository
+}
+
+func (r *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	start := time.Now()
+	result, err := r.next.GetAPIKeyByHash(ctx, hash)
+	observe("api_keys", "GetAPIKeyByHash", start, err)
+	return result, err
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key models.APIKey) (int, error) {
+	start := time.Now()
+	result, err := r.next.CreateAPIKey(ctx, key)
+	observe("api_keys", "CreateAPIKey", start, err)
+	return result, err
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.RevokeAPIKey(ctx, id)
+	observe("api_keys", "RevokeAPIKey", start, err)
+	return err
+}
+
+func (r *apiKeyRepository) SetAPIKeyRole(ctx context.Context, id int, role string) error {
+	start := time.Now()
+	err := r.next.SetAPIKeyRole(ctx, id, role)
+	observe("api_keys", "SetAPIKeyRole", start, err)
+	return err
+}
+
+// userRepository instruments a models.UserRepository.
+type userRepository struct {
+	next models.UserRepository
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id int) (models.User, error) {
+	start := time.Now()
+	result, err := r.next.GetUserByID(ctx, id)
+	observe("users", "GetUserByID", start, err)
+	return result, err
+}
+
+func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	start := time.Now()
+	result, err := r.next.GetUserByEmail(ctx, email)
+	observe("users", "GetUserByEmail", start, err)
+	return result, err
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user models.User) (int, error) {
+	start := time.Now()
+	result, err := r.next.CreateUser(ctx, user)
+	observe("users", "CreateUser", start, err)
+	return result, err
+}
+
+func (r *userRepository) SetUserRole(ctx context.Context, id int, role string) error {
+	start := time.Now()
+	err := r.next.SetUserRole(ctx, id, role)
+	observe("users", "SetUserRole", start, err)
+	return err
+}
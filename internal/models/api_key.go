@@ -0,0 +1,40 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// APIKey is a key machine clients authenticate with. Only the SHA-256 hash of
+// the key is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Hash is the hex-encoded SHA-256 hash of the key.
+	Hash string `json:"-"`
+	// Role sets the permissions of the clients using the key, like the role
+	// of a user.
+	Role      string     `json:"role"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Revoked reports whether the key was revoked and may no longer be used.
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// APIKeyRepository defines the persistence operations for API keys.
+type APIKeyRepository interface {
+	// GetAPIKeyByHash retrieves the key with the hash, revoked or not. It
+	// returns an error matching ErrNotFound when there is none.
+	GetAPIKeyByHash(ctx context.Context, hash string) (APIKey, error)
+	// CreateAPIKey stores a key and returns its ID.
+	CreateAPIKey(ctx context.Context, key APIKey) (int, error)
+	// RevokeAPIKey revokes a key. Revoking a revoked key keeps the time it
+	// was first revoked.
+	RevokeAPIKey(ctx context.Context, id int) error
+	// SetAPIKeyRole changes the role of a key. It returns an error matching
+	// ErrNotFound when there is no key with the ID.
+	SetAPIKeyRole(ctx context.Context, id int, role string) error
+}
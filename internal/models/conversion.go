@@ -0,0 +1,37 @@
+package models
+
+import "github.com/keevferreira/recipes-api/internal/units"
+
+// ConvertedTo returns a copy of the recipe with ingredient quantities and step
+// temperatures expressed in the given system of measurement. Counted items
+// and units the units package does not know are left unchanged.
+func (r Recipe) ConvertedTo(system units.System) Recipe {
+	converted := r
+
+	converted.Ingredients = make([]RecipeIngredient, len(r.Ingredients))
+	for i, ingredient := range r.Ingredients {
+		quantity, unit := units.ToSystem(ingredient.Quantity, ingredient.Unit, system)
+		if unit != ingredient.Unit {
+			ingredient.Quantity = RoundQuantity(quantity, unit)
+			ingredient.Unit = unit
+		}
+		converted.Ingredients[i] = ingredient
+	}
+
+	if r.Steps != nil {
+		converted.Steps = make([]Step, len(r.Steps))
+		for i, step := range r.Steps {
+			if step.Temperature != nil {
+				temperature, unit := units.ToSystem(*step.Temperature, step.TemperatureUnit, system)
+				if unit != step.TemperatureUnit {
+					temperature = RoundQuantity(temperature, unit)
+					step.Temperature = &temperature
+					step.TemperatureUnit = unit
+				}
+			}
+			converted.Steps[i] = step
+		}
+	}
+
+	return converted
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kinds of domain errors. The repositories return the typed errors below,
+// which match these with errors.Is.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrForbidden  = errors.New("forbidden")
+	ErrValidation = errors.New("validation failed")
+)
+
+// NotFoundError reports that a record does not exist.
+type NotFoundError struct {
+	// Resource names the kind of record, such as "recipe".
+	Resource string
+	ID       int
+}
+
+// NewNotFoundError creates a NotFoundError for the record of resource with id.
+func NewNotFoundError(resource string, id int) error {
+	return &NotFoundError{Resource: resource, ID: id}
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s with ID %d not found", e.Resource, e.ID)
+}
+
+// Is makes errors.Is(err, ErrNotFound) hold.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ConflictError reports that an operation clashes with the current state of
+// the data, such as deleting a record other records still use.
+type ConflictError struct {
+	Message string
+}
+
+// NewConflictError creates a ConflictError with a formatted message.
+func NewConflictError(format string, args ...any) error {
+	return &ConflictError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+// Is makes errors.Is(err, ErrConflict) hold.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ForbiddenError reports that the caller may not perform an operation, such
+// as changing a recipe written by someone else.
+type ForbiddenError struct {
+	Message string
+}
+
+// NewForbiddenError creates a ForbiddenError with a formatted message.
+func NewForbiddenError(format string, args ...any) error {
+	return &ForbiddenError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+// Is makes errors.Is(err, ErrForbidden) hold.
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// Codes of the field errors.
+const (
+	FieldRequired = "required"
+	FieldInvalid  = "invalid"
+	FieldNotFound = "not_found"
+	FieldUnknown  = "unknown"
+)
+
+// FieldError is the validation failure of one field of the input.
+type FieldError struct {
+	// Field is the path of the field in the JSON input, such as
+	// "ingredients[0].quantity". It is empty when the failure is not tied to
+	// a single field.
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError reports invalid input, field by field.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError creates a ValidationError from its field errors.
+func NewValidationError(fields ...FieldError) error {
+	return &ValidationError{Fields: fields}
+}
+
+// NewFieldError creates a ValidationError for a single field.
+func NewFieldError(field string, code string, format string, args ...any) error {
+	return NewValidationError(FieldError{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		if field.Field == "" {
+			messages = append(messages, field.Message)
+		} else {
+			messages = append(messages, field.Field+": "+field.Message)
+		}
+	}
+
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Is makes errors.Is(err, ErrValidation) hold.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sort keys accepted by the collection reads. Every collection can be sorted
+// by SortByID and SortByCreatedAt; the other keys are listed with the
+// repository method that accepts them.
+const (
+	SortByID        = "id"
+	SortByCreatedAt = "created_at"
+	SortByTitle     = "title"
+	SortByPrepTime  = "prep_time"
+	SortByName      = "name"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a sorted collection by the ID of an item. A read
+// with a cursor returns the items right after it, or right before it when
+// Before is set, so that pages stay stable while rows are inserted.
+type Cursor struct {
+	ID     int
+	Before bool
+}
+
+// Encode returns the opaque form of the cursor used in URLs.
+func (c Cursor) Encode() string {
+	direction := "a"
+	if c.Before {
+		direction = "b"
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(direction + ":" + strconv.Itoa(c.ID)))
+}
+
+// ParseCursor decodes a cursor returned by Cursor.Encode.
+func ParseCursor(value string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	direction, id, ok := strings.Cut(string(raw), ":")
+	if !ok || (direction != "a" && direction != "b") {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	cursor := Cursor{Before: direction == "b"}
+	cursor.ID, err = strconv.Atoi(id)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// ListOptions holds the pagination and sorting of a collection read.
+type ListOptions struct {
+	// Limit is the maximum number of items returned.
+	Limit int
+	// Offset skips that many items. It is ignored when Cursor is set.
+	Offset int
+	Cursor *Cursor
+	// Sort is one of the sort keys accepted by the collection. Ties are
+	// broken by ID, in the same direction.
+	Sort string
+	Desc bool
+}
+
+// CheckSort reports an error if the sort key is not one of keys.
+func (o ListOptions) CheckSort(keys ...string) error {
+	for _, key := range keys {
+		if o.Sort == key {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot sort by %q, use one of %s", o.Sort, strings.Join(keys, ", "))
+}
+
+// Backwards reports whether the items must be read in the reverse of the
+// requested order, which is the case when paging before a cursor.
+func (o ListOptions) Backwards() bool {
+	return o.Cursor != nil && o.Cursor.Before
+}
+
+// PageInfo describes where a page sits in its collection.
+type PageInfo struct {
+	// Total is the number of items matching the filters, across all pages.
+	Total   int
+	HasNext bool
+	HasPrev bool
+}
+
+// pageInfo computes the page information of a read that fetched up to
+// Limit+1 items, the extra one telling whether the read stopped early.
+func (o ListOptions) pageInfo(total int, fetched int) PageInfo {
+	more := fetched > o.Limit
+
+	switch {
+	case o.Cursor == nil:
+		return PageInfo{Total: total, HasNext: more, HasPrev: o.Offset > 0}
+	case o.Cursor.Before:
+		return PageInfo{Total: total, HasNext: true, HasPrev: more}
+	default:
+		return PageInfo{Total: total, HasNext: more, HasPrev: true}
+	}
+}
+
+// Page trims items read with Limit+1 to the page size and puts them back in
+// the requested order. It returns the page and its information.
+func Page[T any](o ListOptions, items []T, total int) ([]T, PageInfo) {
+	info := o.pageInfo(total, len(items))
+	if len(items) > o.Limit {
+		items = items[:o.Limit]
+	}
+
+	if o.Backwards() {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	return items, info
+}
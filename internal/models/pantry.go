@@ -0,0 +1,85 @@
+package models
+
+import (
+	"context"
+	"sort"
+
+	"github.com/keevferreira/recipes-api/internal/units"
+)
+
+// PantryItem is an ingredient available in the pantry.
+type PantryItem struct {
+	IngredientID int `json:"ingredient_id"`
+	// Name is the catalog name of the ingredient, filled in on reads.
+	Name string `json:"name"`
+	// Quantity is nil when the amount available is unknown.
+	Quantity *float64 `json:"quantity,omitempty"`
+	Unit     string   `json:"unit,omitempty"`
+}
+
+// CookableRecipe is a recipe matched against the pantry. Missing lists the
+// required ingredient lines the pantry lacks or does not have enough of.
+type CookableRecipe struct {
+	RecipeID         int                `json:"recipe_id"`
+	Title            string             `json:"title"`
+	TotalIngredients int                `json:"total_ingredients"`
+	Missing          []RecipeIngredient `json:"missing"`
+}
+
+// PantryRepository defines the persistence operations for the pantries and
+// the search of recipes that can be cooked with them. Each owner, the subject
+// of a principal, has its own pantry.
+type PantryRepository interface {
+	// GetPantryItems retrieves every item in the pantry of owner.
+	GetPantryItems(ctx context.Context, owner string) ([]PantryItem, error)
+	// SetPantryItem adds an ingredient to the pantry of owner or replaces its
+	// quantity.
+	SetPantryItem(ctx context.Context, owner string, item PantryItem) error
+	// DeletePantryItem removes an ingredient from the pantry of owner.
+	DeletePantryItem(ctx context.Context, owner string, ingredientID int) error
+	// FindCookableRecipes returns up to limit recipes missing at most
+	// maxMissing required ingredients from the pantry of owner, ranked by
+	// the number missing. Optional ingredient lines are ignored.
+	FindCookableRecipes(ctx context.Context, owner string, maxMissing int, limit int) ([]CookableRecipe, error)
+}
+
+// Covers reports whether the pantry item is enough for the ingredient line.
+// Items without a quantity, and quantities in units that cannot be compared,
+// are assumed to be enough.
+func (p PantryItem) Covers(line RecipeIngredient) bool {
+	if p.Quantity == nil {
+		return true
+	}
+
+	available, availableUnit := units.Base(*p.Quantity, p.Unit)
+	needed, neededUnit := units.Base(line.Quantity, line.Unit)
+	if availableUnit != neededUnit {
+		return true
+	}
+
+	return available >= needed
+}
+
+// RankCookableRecipes orders recipes by the number of missing ingredients,
+// then by the number of ingredients they use, and drops the ones missing
+// more than maxMissing.
+func RankCookableRecipes(recipes []CookableRecipe, maxMissing int) []CookableRecipe {
+	ranked := recipes[:0]
+	for _, recipe := range recipes {
+		if len(recipe.Missing) <= maxMissing {
+			ranked = append(ranked, recipe)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if len(ranked[i].Missing) != len(ranked[j].Missing) {
+			return len(ranked[i].Missing) < len(ranked[j].Missing)
+		}
+		if ranked[i].TotalIngredients != ranked[j].TotalIngredients {
+			return ranked[i].TotalIngredients > ranked[j].TotalIngredients
+		}
+		return ranked[i].RecipeID < ranked[j].RecipeID
+	})
+
+	return ranked
+}
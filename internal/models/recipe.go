@@ -1,231 +1,87 @@
 package models
 
 import (
-	"database/sql"
-	"fmt"
+	"context"
 	"time"
-
-	"github.com/keevferreira/recipes-api/internal/database"
 )
 
 type Recipe struct {
-	ID          int          `json:"id"`
-	Title       string       `json:"title"`
-	Description string       `json:"description"`
-	Ingredients []Ingredient `json:"ingredients"`
-	Categories  []Category   `json:"category"`
-	PrepTime    int          `json:"prep_time"`
-	Difficulty  string       `json:"difficulty"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
-}
-
-// Recipes represents a collection of recipes.
-type Recipes []Recipe
-
-// GetRecipeByID retrieves a recipe by its ID from the database.
-func GetRecipeByID(id int) (Recipe, error) {
-	var recipe Recipe
-
-	err := database.DB.QueryRow("SELECT id, title, description, prep_time, difficulty, created_at, updated_at FROM recipes WHERE id = ?", id).
-		Scan(&recipe.ID, &recipe.Title, &recipe.Description, &recipe.PrepTime, &recipe.Difficulty, &recipe.CreatedAt, &recipe.UpdatedAt)
-
-	switch {
-	case err == sql.ErrNoRows:
-		return Recipe{}, fmt.Errorf("recipe with ID %d not found", id)
-	case err != nil:
-		return Recipe{}, err
-	}
-
-	// Fetch ingredients and categories from the database
-	recipe.Ingredients, err = GetIngredientsByRecipeID(id)
-	if err != nil {
-		return Recipe{}, err
-	}
-
-	recipe.Categories, err = GetCategoriesByRecipeID(id)
-	if err != nil {
-		return Recipe{}, err
-	}
-
-	return recipe, nil
-}
-
-// UpdateRecipeByID updates a recipe by its ID in the database.
-func UpdateRecipeByID(id int, updatedRecipe Recipe) error {
-	tx, err := database.DB.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-			return
-		}
-		tx.Commit()
-	}()
-
-	_, err = tx.Exec("UPDATE recipes SET title=?, description=?, prep_time=?, difficulty=?, updated_at=? WHERE id=?",
-		updatedRecipe.Title, updatedRecipe.Description, updatedRecipe.PrepTime, updatedRecipe.Difficulty, time.Now(), id)
-	if err != nil {
-		return err
-	}
-
-	err = UpdateIngredientsByRecipeID(id, updatedRecipe.Ingredients)
-	if err != nil {
-		return err
-	}
-
-	err = UpdateCategoriesByRecipeID(id, updatedRecipe.Categories)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// DeleteRecipeByID deletes a recipe by its ID from the database.
-func DeleteRecipeByID(id int) error {
-	tx, err := database.DB.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-			return
-		}
-		tx.Commit()
-	}()
-
-	_, err = tx.Exec("DELETE FROM recipes WHERE id=?", id)
-	if err != nil {
-		return err
-	}
-
-	err = DeleteIngredientsByRecipeID(id)
-	if err != nil {
-		return err
-	}
-
-	err = DeleteCategoryByRecipeID(id)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	ID          int                `json:"id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	Categories  []Category         `json:"category"`
+	Steps       []Step             `json:"steps,omitempty"`
+	PrepTime    int                `json:"prep_time"`
+	// Servings is how many portions the recipe yields, or 0 if unknown.
+	Servings   int    `json:"servings"`
+	Difficulty string `json:"difficulty"`
+	// AuthorID is the ID of the user who created the recipe, or 0 if the
+	// recipe has no author. It is set from the caller, never from the input.
+	AuthorID  int       `json:"author_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// GetAllRecipes retrieves all recipes from the database.
-func GetAllRecipes() ([]Recipe, error) {
-	var recipes []Recipe
-
-	rows, err := database.DB.Query("SELECT id, title, description, preptime, difficulty, createdat, updatedat FROM recipe")
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var recipe Recipe
-		err := rows.Scan(&recipe.ID, &recipe.Title, &recipe.Description, &recipe.PrepTime, &recipe.Difficulty, &recipe.CreatedAt, &recipe.UpdatedAt)
-		if err != nil {
-			return nil, err
-		}
-
-		recipe.Ingredients, err = GetIngredientsByRecipeID(recipe.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		recipe.Categories, err = GetCategoriesByRecipeID(recipe.ID)
-		if err != nil {
-			return nil, err
-		}
+// Difficulty levels of a recipe.
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
 
-		recipes = append(recipes, recipe)
-	}
+// Difficulties lists the difficulty levels a recipe can have.
+var Difficulties = []string{DifficultyEasy, DifficultyMedium, DifficultyHard}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
+// Recipes represents a collection of recipes.
+type Recipes []Recipe
 
-	return recipes, nil
+// RecipeSortKeys are the sort keys accepted by RecipeRepository.GetAllRecipes.
+var RecipeSortKeys = []string{SortByID, SortByTitle, SortByPrepTime, SortByCreatedAt}
+
+// RecipeFilter narrows a read of recipes. Zero fields do not filter.
+type RecipeFilter struct {
+	Difficulty  string
+	MaxPrepTime int
+	// CategoryID keeps the recipes in the category.
+	CategoryID int
+	// IngredientID keeps the recipes that use the ingredient, optional or not.
+	IngredientID int
+	// AuthorID keeps the recipes created by the user.
+	AuthorID int
 }
 
-// CreateRecipe creates a new recipe in the database.
-func CreateRecipe(recipe Recipe) (int, error) {
-	tx, err := database.DB.Begin()
-	if err != nil {
-		return 0, err
-	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-			return
-		}
-		tx.Commit()
-	}()
-
-	result, err := tx.Exec("INSERT INTO recipe (title, description, prep_ime, difficulty, createdat, updatedat) VALUES (?, ?, ?, ?, ?, ?)",
-		recipe.Title, recipe.Description, recipe.PrepTime, recipe.Difficulty, time.Now(), time.Now())
-	if err != nil {
-		return 0, err
-	}
-
-	id, err := result.LastInsertId()
-	if err != nil {
-		return 0, err
-	}
-
-	err = InsertIngredientsAndCategories(int(id), recipe.Ingredients, recipe.Categories)
-	if err != nil {
-		return 0, err
-	}
-
-	return int(id), nil
+// RecipeIncludes selects the associations loaded with a list of recipes.
+// Leaving them out spares the queries when only the recipes are needed.
+type RecipeIncludes struct {
+	Ingredients bool
+	Categories  bool
 }
 
-// InsertIngredientsAndCategories inserts ingredients and associates categories with a recipe by its ID in the database.
-func InsertIngredientsAndCategories(recipeID int, ingredients []Ingredient, categories []Category) error {
-	// Begin a transaction
-	tx, err := database.DB.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			// Rollback the transaction if an error occurs
-			tx.Rollback()
-			return
-		}
-	}()
-
-	// Insert ingredients and associate them with the recipe
-	for _, ingredient := range ingredients {
-		_, err := tx.Exec("INSERT INTO recipeingredients (recipeid, ingredientid) VALUES (?, ?)", recipeID, ingredient.ID)
-		if err != nil {
-			// Rollback the transaction if an error occurs
-			return err
-		}
-	}
-
-	// Associate categories with the recipe
-	for _, category := range categories {
-		_, err := tx.Exec("INSERT INTO recipecategories (recipeid, categoryid) VALUES (?, ?)", recipeID, category.ID)
-		if err != nil {
-			// Rollback the transaction if an error occurs
-			return err
-		}
-	}
-
-	// Commit the transaction
-	err = tx.Commit()
-	if err != nil {
-		// Rollback the transaction if an error occurs during commit
-		tx.Rollback()
-		return err
-	}
-
-	return nil
+// RecipeRepository defines the persistence operations for recipes.
+// Single recipes are returned with their ingredients and categories loaded.
+// Steps are written with the recipe, positioned in slice order.
+type RecipeRepository interface {
+	// GetRecipeByID retrieves a recipe by its ID, including its steps.
+	GetRecipeByID(ctx context.Context, id int) (Recipe, error)
+	// GetAllRecipes retrieves a page of the recipes matching filter, loading
+	// the associations selected by includes with a constant number of queries.
+	GetAllRecipes(ctx context.Context, filter RecipeFilter, includes RecipeIncludes, options ListOptions) ([]Recipe, PageInfo, error)
+	// CreateRecipe creates a new recipe, linking its ingredients and categories, and returns its ID.
+	CreateRecipe(ctx context.Context, recipe Recipe) (int, error)
+	// UpdateRecipeByID updates a recipe by its ID, replacing its ingredients and categories.
+	// Steps with the ID of a current step are updated in place, steps without an ID are
+	// added and the current steps left out are removed.
+	UpdateRecipeByID(ctx context.Context, id int, updatedRecipe Recipe) error
+	// DeleteRecipeByID deletes a recipe by its ID along with its ingredient and category links.
+	DeleteRecipeByID(ctx context.Context, id int) error
+	// SearchRecipes runs a full-text search over the title, description,
+	// ingredient names and steps of the recipes and returns a page of the
+	// results, best ranked first. Only the Limit and Offset of options apply.
+	SearchRecipes(ctx context.Context, query string, options ListOptions) ([]RecipeSearchResult, PageInfo, error)
+	// GetStepsByRecipeID retrieves the steps of a recipe ordered by position.
+	GetStepsByRecipeID(ctx context.Context, recipeID int) ([]Step, error)
+	// ReorderStepsByRecipeID reorders the steps of a recipe. stepIDs must list
+	// every step of the recipe exactly once, in the new order.
+	ReorderStepsByRecipeID(ctx context.Context, recipeID int, stepIDs []int) error
 }
@@ -0,0 +1,13 @@
+package models
+
+// Repositories groups the repositories used by the API so that a storage
+// backend can be injected as a whole.
+type Repositories struct {
+	Recipes       RecipeRepository
+	Ingredients   IngredientRepository
+	Categories    CategoryRepository
+	ShoppingLists ShoppingListRepository
+	Pantry        PantryRepository
+	APIKeys       APIKeyRepository
+	Users         UserRepository
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"math"
+	"strings"
+
+	"github.com/keevferreira/recipes-api/internal/units"
+)
+
+// roundingSteps maps a unit to the step its scaled quantities are rounded
+// to. Known units are looked up by their units.Unit symbol, anything else in
+// lower case. Units missing from the map are rounded to two decimals.
+var roundingSteps = map[string]float64{
+	// Counted items: no "0.3333 eggs"
+	"":       1,
+	"un":     1,
+	"dúzia":  0.5,
+	"dente":  1,
+	"dentes": 1,
+	"clove":  1,
+	"cloves": 1,
+	"pitada": 1,
+	"pinch":  1,
+
+	// Kitchen measures: quarters
+	"xícara":         0.25,
+	"colher de sopa": 0.25,
+	"colher de chá":  0.25,
+	"colher":         0.25,
+	"colheres":       0.25,
+	"cup":            0.25,
+	"tbsp":           0.25,
+	"tsp":            0.25,
+	"pint":           0.25,
+	"quart":          0.25,
+	"gallon":         0.25,
+	"fl oz":          0.5,
+
+	// Larger units
+	"kg": 0.05,
+	"l":  0.05,
+	"lb": 0.25,
+	"oz": 0.5,
+
+	// Temperatures
+	"°C": 1,
+	"°F": 1,
+	"K":  1,
+}
+
+// fineUnits are the small metric units whose rounding step grows with the quantity.
+var fineUnits = map[string]bool{"g": true, "mg": true, "ml": true}
+
+// Scaled returns a copy of the recipe with every ingredient quantity
+// multiplied by factor and rounded for its unit. Servings is scaled as well.
+func (r Recipe) Scaled(factor float64) Recipe {
+	scaled := r
+	scaled.Ingredients = make([]RecipeIngredient, len(r.Ingredients))
+	for i, ingredient := range r.Ingredients {
+		ingredient.Quantity = RoundQuantity(ingredient.Quantity*factor, ingredient.Unit)
+		scaled.Ingredients[i] = ingredient
+	}
+	scaled.Servings = int(math.Round(float64(r.Servings) * factor))
+
+	return scaled
+}
+
+// ScaleFactorForServings returns the factor that makes the recipe yield servings portions.
+func (r Recipe) ScaleFactorForServings(servings int) (float64, error) {
+	if r.Servings <= 0 {
+		return 0, NewConflictError("recipe with ID %d has no servings to scale from", r.ID)
+	}
+	if servings <= 0 {
+		return 0, NewFieldError("servings", FieldInvalid, "servings must be greater than zero")
+	}
+
+	return float64(servings) / float64(r.Servings), nil
+}
+
+// ScaleFactorForIngredient returns the factor that makes the recipe use
+// amount of the given ingredient, expressed in unit. An empty unit stands for
+// the unit of the recipe. Other units are converted to it, going between
+// volume and mass through density, in grams per milliliter, when positive.
+func (r Recipe) ScaleFactorForIngredient(ingredientID int, amount float64, unit string, density float64) (float64, error) {
+	if amount <= 0 {
+		return 0, NewFieldError("amount", FieldInvalid, "amount must be greater than zero")
+	}
+
+	for _, ingredient := range r.Ingredients {
+		if ingredient.IngredientID != ingredientID {
+			continue
+		}
+		if ingredient.Quantity <= 0 {
+			return 0, NewConflictError("ingredient with ID %d has no quantity in recipe with ID %d", ingredientID, r.ID)
+		}
+
+		if unit != "" && !strings.EqualFold(strings.TrimSpace(unit), strings.TrimSpace(ingredient.Unit)) {
+			converted, err := units.ConvertWithDensity(amount, unit, ingredient.Unit, density)
+			if err != nil {
+				return 0, NewFieldError("unit", FieldInvalid, "%s", err.Error())
+			}
+			amount = converted
+		}
+
+		return amount / ingredient.Quantity, nil
+	}
+
+	return 0, NewFieldError("ingredient_id", FieldNotFound, "ingredient with ID %d is not used by recipe with ID %d", ingredientID, r.ID)
+}
+
+// RoundQuantity rounds a scaled quantity to a step that makes sense for its
+// unit. Positive quantities never round down to zero.
+func RoundQuantity(quantity float64, unit string) float64 {
+	if quantity <= 0 {
+		return quantity
+	}
+
+	if known, ok := units.Lookup(unit); ok {
+		unit = known.Symbol
+	} else {
+		unit = strings.ToLower(strings.TrimSpace(unit))
+	}
+
+	step, ok := roundingSteps[unit]
+	switch {
+	case ok:
+	case fineUnits[unit]:
+		step = fineStep(quantity)
+	default:
+		step = 0.01
+	}
+
+	rounded := math.Round(quantity/step) * step
+	if rounded == 0 {
+		rounded = step
+	}
+
+	// Drop the floating point noise left by the division above
+	return math.Round(rounded*100) / 100
+}
+
+// fineStep returns the rounding step for grams and milliliters, coarser as the quantity grows.
+func fineStep(quantity float64) float64 {
+	switch {
+	case quantity < 10:
+		return 0.5
+	case quantity < 100:
+		return 1
+	case quantity < 1000:
+		return 5
+	default:
+		return 10
+	}
+}
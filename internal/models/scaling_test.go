@@ -0,0 +1,133 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestRoundQuantity(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		unit     string
+		want     float64
+	}{
+		{1.4, "un", 1},
+		{0.3, "un", 1},
+		{2.6, "", 3},
+		{0.8, "dúzia", 1},
+		{1.1, "xícara", 1},
+		{1.4, "xicaras", 1.5},
+		{0.3, "tbsp", 0.25},
+		{3.3, "g", 3.5},
+		{47.4, "g", 47},
+		{333.3, "gramas", 335},
+		{1234, "ml", 1230},
+		{1.234, "kg", 1.25},
+		{0.02, "kg", 0.05},
+		{181.6, "°C", 182},
+		{1.234, "maço", 1.23},
+		{0, "g", 0},
+	}
+
+	for _, test := range tests {
+		got := RoundQuantity(test.quantity, test.unit)
+		if math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("RoundQuantity(%v, %q) = %v, want %v", test.quantity, test.unit, got, test.want)
+		}
+	}
+}
+
+func TestScaled(t *testing.T) {
+	recipe := Recipe{
+		Servings: 4,
+		Ingredients: []RecipeIngredient{
+			{IngredientID: 1, Quantity: 200, Unit: "g"},
+			{IngredientID: 2, Quantity: 3, Unit: "un"},
+		},
+	}
+
+	scaled := recipe.Scaled(1.5)
+	if scaled.Servings != 6 {
+		t.Errorf("Servings = %d, want 6", scaled.Servings)
+	}
+	if scaled.Ingredients[0].Quantity != 300 || scaled.Ingredients[1].Quantity != 5 {
+		t.Errorf("Ingredients = %+v, want 300 g and 5 un", scaled.Ingredients)
+	}
+	if recipe.Ingredients[0].Quantity != 200 {
+		t.Errorf("Scaled changed the original recipe: %+v", recipe.Ingredients)
+	}
+}
+
+func TestScaleFactorForServings(t *testing.T) {
+	tests := []struct {
+		name     string
+		recipe   Recipe
+		servings int
+		want     float64
+		err      error
+	}{
+		{"double", Recipe{Servings: 4}, 8, 2, nil},
+		{"half", Recipe{Servings: 4}, 2, 0.5, nil},
+		{"no servings", Recipe{ID: 1}, 2, 0, ErrConflict},
+		{"zero servings", Recipe{Servings: 4}, 0, 0, ErrValidation},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.recipe.ScaleFactorForServings(test.servings)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error = %v, want %v", err, test.err)
+			}
+			if got != test.want {
+				t.Errorf("factor = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScaleFactorForIngredient(t *testing.T) {
+	recipe := Recipe{
+		ID: 1,
+		Ingredients: []RecipeIngredient{
+			{IngredientID: 1, Quantity: 200, Unit: "g"},
+			{IngredientID: 2, Quantity: 2, Unit: "xícara"},
+			{IngredientID: 3, Quantity: 0, Unit: "pitada"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		ingredientID int
+		amount       float64
+		unit         string
+		density      float64
+		want         float64
+		err          error
+	}{
+		{"unit of the recipe", 1, 400, "", 0, 2, nil},
+		{"same unit", 1, 100, "g", 0, 0.5, nil},
+		{"same unit in another case", 2, 1, "Xícara", 0, 0.5, nil},
+		{"converted unit", 1, 1, "kg", 0, 5, nil},
+		{"alias", 1, 0.5, "quilo", 0, 2.5, nil},
+		{"volume to mass", 1, 1, "xícara", 0.5, 0.6, nil},
+		{"mass to volume without density", 2, 240, "g", 0, 0, ErrValidation},
+		{"other dimension", 1, 2, "un", 0, 0, ErrValidation},
+		{"unknown unit", 1, 2, "punhado", 0, 0, ErrValidation},
+		{"no quantity", 3, 1, "", 0, 0, ErrConflict},
+		{"not in the recipe", 9, 1, "", 0, 0, ErrValidation},
+		{"no amount", 1, 0, "", 0, 0, ErrValidation},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := recipe.ScaleFactorForIngredient(test.ingredientID, test.amount, test.unit, test.density)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error = %v, want %v", err, test.err)
+			}
+			if math.Abs(got-test.want) > 1e-9 {
+				t.Errorf("factor = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
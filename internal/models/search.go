@@ -0,0 +1,14 @@
+package models
+
+// RecipeSearchResult is a recipe matched by a full-text search.
+type RecipeSearchResult struct {
+	RecipeID int    `json:"recipe_id"`
+	Title    string `json:"title"`
+	// Rank orders the results, higher first. Its scale depends on the
+	// storage backend and is only meaningful within one search.
+	Rank float64 `json:"rank"`
+	// Snippet is an excerpt of the recipe with the matched words wrapped in
+	// <mark> tags. The recipe text is HTML escaped, so the snippet is safe
+	// to render as HTML.
+	Snippet string `json:"snippet"`
+}
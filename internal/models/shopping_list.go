@@ -0,0 +1,134 @@
+package models
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/keevferreira/recipes-api/internal/units"
+)
+
+// ShoppingList is a saved list of ingredients to buy for a set of recipes.
+type ShoppingList struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Owner is the subject of the principal that created the list, such as
+	// "user:1". Lists created before lists had owners have none.
+	Owner     string             `json:"owner"`
+	Items     []ShoppingListItem `json:"items"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// ShoppingListItem is the total amount of one ingredient to buy.
+type ShoppingListItem struct {
+	ID           int `json:"id"`
+	IngredientID int `json:"ingredient_id"`
+	// Name is the catalog name of the ingredient, filled in on reads.
+	Name     string  `json:"name"`
+	Aisle    string  `json:"aisle"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Checked  bool    `json:"checked"`
+}
+
+// ShoppingListAisle groups the items of a shopping list found in the same aisle.
+type ShoppingListAisle struct {
+	Aisle string             `json:"aisle"`
+	Items []ShoppingListItem `json:"items"`
+}
+
+// ShoppingListRepository defines the persistence operations for shopping lists.
+type ShoppingListRepository interface {
+	// GetShoppingListByID retrieves a shopping list with its items.
+	GetShoppingListByID(ctx context.Context, id int) (ShoppingList, error)
+	// CreateShoppingList creates a shopping list with its items and returns its ID.
+	CreateShoppingList(ctx context.Context, list ShoppingList) (int, error)
+	// DeleteShoppingListByID deletes a shopping list and its items.
+	DeleteShoppingListByID(ctx context.Context, id int) error
+	// CheckShoppingListItem marks an item of a shopping list as bought or not.
+	CheckShoppingListItem(ctx context.Context, listID int, itemID int, checked bool) error
+}
+
+// MergeShoppingItems sums the given ingredient lines into shopping list
+// items, one per ingredient and kind of unit. Quantities are normalized to
+// grams, milliliters or items before being summed, so "1 kg" and "500 g" of
+// flour become "1.5 kg". Volumes are turned into mass when the ingredient
+// has a density. catalog provides the density and aisle of each ingredient.
+func MergeShoppingItems(lines []RecipeIngredient, catalog map[int]Ingredient) []ShoppingListItem {
+	type key struct {
+		ingredientID int
+		unit         string
+	}
+
+	var order []key
+	totals := make(map[key]*ShoppingListItem)
+
+	for _, line := range lines {
+		ingredient := catalog[line.IngredientID]
+		quantity, unit := units.Base(line.Quantity, line.Unit)
+		if unit == "ml" && ingredient.Density != nil && *ingredient.Density > 0 {
+			quantity, unit = quantity*(*ingredient.Density), "g"
+		}
+
+		k := key{ingredientID: line.IngredientID, unit: unit}
+		item, ok := totals[k]
+		if !ok {
+			item = &ShoppingListItem{
+				IngredientID: line.IngredientID,
+				Name:         ingredient.Name,
+				Aisle:        ingredient.Aisle,
+				Unit:         unit,
+			}
+			totals[k] = item
+			order = append(order, k)
+		}
+		item.Quantity += quantity
+	}
+
+	items := make([]ShoppingListItem, 0, len(order))
+	for _, k := range order {
+		item := *totals[k]
+		item.Quantity, item.Unit = units.Readable(item.Quantity, item.Unit)
+		item.Quantity = RoundQuantity(item.Quantity, item.Unit)
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Aisle != items[j].Aisle {
+			return aisleLess(items[i].Aisle, items[j].Aisle)
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	return items
+}
+
+// GroupByAisle groups the items of the list by aisle, in aisle order with
+// the items without an aisle last.
+func (l ShoppingList) GroupByAisle() []ShoppingListAisle {
+	var aisles []ShoppingListAisle
+	index := make(map[string]int)
+
+	for _, item := range l.Items {
+		i, ok := index[item.Aisle]
+		if !ok {
+			i = len(aisles)
+			index[item.Aisle] = i
+			aisles = append(aisles, ShoppingListAisle{Aisle: item.Aisle})
+		}
+		aisles[i].Items = append(aisles[i].Items, item)
+	}
+
+	sort.SliceStable(aisles, func(i, j int) bool { return aisleLess(aisles[i].Aisle, aisles[j].Aisle) })
+
+	return aisles
+}
+
+// aisleLess orders aisles by name, leaving items without an aisle for last.
+func aisleLess(a string, b string) bool {
+	if a == "" || b == "" {
+		return b == "" && a != ""
+	}
+	return a < b
+}
@@ -0,0 +1,17 @@
+package models
+
+// Step is one ordered preparation step of a recipe.
+type Step struct {
+	ID       int    `json:"id"`
+	Position int    `json:"position"`
+	Text     string `json:"text"`
+	// Duration is how long the step takes, in minutes.
+	Duration *int `json:"duration,omitempty"`
+	// Temperature is the cooking temperature, measured in TemperatureUnit.
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TemperatureUnit string   `json:"temperature_unit,omitempty"`
+	// IngredientIDs references the recipe ingredients used in this step.
+	IngredientIDs []int `json:"ingredient_ids,omitempty"`
+}
+
+type Steps []Step
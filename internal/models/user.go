@@ -0,0 +1,58 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Roles of the users, from the least to the most privileged. Viewers only
+// read; authors write their own recipes; editors also curate the shared
+// catalogs and every recipe; admins also manage the users. The permissions of
+// each role are set in the auth package.
+const (
+	RoleViewer = "viewer"
+	RoleAuthor = "author"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Roles lists the valid roles, from the least to the most privileged.
+var Roles = []string{RoleViewer, RoleAuthor, RoleEditor, RoleAdmin}
+
+// User is an account that signs in with an e-mail and a password.
+type User struct {
+	ID int `json:"id"`
+	// Email is stored in lower case, so that it is matched regardless of case.
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	// PasswordHash is the bcrypt hash of the password.
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Registration is the input that creates a user.
+type Registration struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// RoleAssignment is the input that changes the role of a user.
+type RoleAssignment struct {
+	Role string `json:"role"`
+}
+
+// UserRepository defines the persistence operations for users.
+type UserRepository interface {
+	GetUserByID(ctx context.Context, id int) (User, error)
+	// GetUserByEmail retrieves the user with the e-mail. It returns an error
+	// matching ErrNotFound when there is none.
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	// CreateUser stores a user and returns its ID. It returns a ConflictError
+	// when the e-mail is taken.
+	CreateUser(ctx context.Context, user User) (int, error)
+	// SetUserRole changes the role of a user.
+	SetUserRole(ctx context.Context, id int, role string) error
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops the buckets that are full
+// again, whose clients stopped sending requests.
+const sweepInterval = time.Minute
+
+// bucket is a token bucket, with the tokens it held when last updated.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// refill adds the tokens earned since the last update, up to the capacity
+// of the bucket.
+func (b *bucket) refill(now time.Time) {
+	rate := float64(b.limit.Requests) / b.limit.Period.Seconds()
+	b.tokens = math.Min(float64(b.limit.Requests), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+}
+
+// timeFor returns how long the bucket takes to earn n more tokens.
+func (b *bucket) timeFor(n float64) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	rate := float64(b.limit.Requests) / b.limit.Period.Seconds()
+	return time.Duration(n / rate * float64(time.Second))
+}
+
+// result describes the bucket once a request was let through or not.
+func (b *bucket) result(allowed bool) Result {
+	result := Result{Allowed: allowed, Remaining: int(b.tokens)}
+	if !allowed {
+		result.RetryAfter = b.timeFor(1 - b.tokens)
+	}
+	result.Reset = b.timeFor(float64(b.limit.Requests) - b.tokens)
+
+	return result
+}
+
+// MemoryStore is a Store keeping the buckets in the memory of the process.
+// Each instance of the service then limits its clients on its own.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now()}
+}
+
+// Take takes a token from the bucket with the key.
+func (ms *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(ms.lastSweep) >= sweepInterval {
+		ms.sweep(now)
+	}
+
+	b, ok := ms.buckets[key]
+	if !ok || b.limit != limit {
+		// A new client, or a limit changed by the configuration, starts full
+		b = &bucket{tokens: float64(limit.Requests), updated: now, limit: limit}
+		ms.buckets[key] = b
+	}
+	b.refill(now)
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return b.result(allowed), nil
+}
+
+// Peek reports the state of the bucket with the key without taking a token.
+func (ms *MemoryStore) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	b, ok := ms.buckets[key]
+	if !ok || b.limit != limit {
+		return Result{Allowed: limit.Requests >= 1, Remaining: limit.Requests}, nil
+	}
+	b.refill(time.Now())
+
+	return b.result(b.tokens >= 1), nil
+}
+
+// sweep drops the buckets that are full again.
+func (ms *MemoryStore) sweep(now time.Time) {
+	for key, b := range ms.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Requests) {
+			delete(ms.buckets, key)
+		}
+	}
+	ms.lastSweep = now
+}
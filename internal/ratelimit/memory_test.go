@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	limit := Limit{Requests: 3, Period: time.Minute}
+
+	tests := []struct {
+		allowed   bool
+		remaining int
+	}{
+		{true, 2},
+		{true, 1},
+		{true, 0},
+		{false, 0},
+		{false, 0},
+	}
+
+	for i, test := range tests {
+		result, err := store.Take(ctx, "client", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed != test.allowed || result.Remaining != test.remaining {
+			t.Errorf("Take #%d = %+v, want allowed %t and %d remaining", i+1, result, test.allowed, test.remaining)
+		}
+		if test.allowed && result.RetryAfter != 0 {
+			t.Errorf("Take #%d RetryAfter = %v, want 0", i+1, result.RetryAfter)
+		}
+		// A token is earned every 20s, and the bucket is full after 20s per
+		// missing token
+		if !test.allowed && (result.RetryAfter <= 0 || result.RetryAfter > 20*time.Second) {
+			t.Errorf("Take #%d RetryAfter = %v, want up to 20s", i+1, result.RetryAfter)
+		}
+		if result.Reset > time.Minute {
+			t.Errorf("Take #%d Reset = %v, want up to 1m", i+1, result.Reset)
+		}
+	}
+
+	if result, _ := store.Take(ctx, "other", limit); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("Take for another key = %+v, want its own bucket", result)
+	}
+
+	// A limit changed by the configuration starts a full bucket
+	if result, _ := store.Take(ctx, "client", Limit{Requests: 5, Period: time.Minute}); !result.Allowed || result.Remaining != 4 {
+		t.Errorf("Take with a new limit = %+v, want a full bucket", result)
+	}
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Period: 100 * time.Millisecond}
+
+	store.Take(ctx, "client", limit)
+	store.Take(ctx, "client", limit)
+	result, _ := store.Take(ctx, "client", limit)
+	if result.Allowed {
+		t.Fatalf("Take = %+v, want an empty bucket", result)
+	}
+
+	time.Sleep(result.RetryAfter + 10*time.Millisecond)
+	if result, _ := store.Take(ctx, "client", limit); !result.Allowed {
+		t.Errorf("Take after %v = %+v, want a refilled token", result.RetryAfter, result)
+	}
+}
+
+func TestMemoryStorePeek(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Period: time.Minute}
+
+	if result, _ := store.Peek(ctx, "client", limit); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("Peek of an unknown key = %+v, want a full bucket", result)
+	}
+
+	store.Take(ctx, "client", limit)
+	for i := 0; i < 2; i++ {
+		if result, _ := store.Peek(ctx, "client", limit); !result.Allowed || result.Remaining != 1 {
+			t.Errorf("Peek = %+v, want 1 remaining and no token taken", result)
+		}
+	}
+
+	store.Take(ctx, "client", limit)
+	result, _ := store.Peek(ctx, "client", limit)
+	if result.Allowed || result.RetryAfter <= 0 {
+		t.Errorf("Peek of an empty bucket = %+v, want denied with a RetryAfter", result)
+	}
+}
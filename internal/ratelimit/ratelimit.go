@@ -0,0 +1,139 @@
+// Package ratelimit limits how many requests each client makes, with token
+// buckets kept in a Store. The buckets live in memory by default; a Store
+// shared by the instances of the service can take their place.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit lets a client make Requests requests per Period. The bucket holds up
+// to Requests tokens, so a client that was idle may spend them all at once,
+// and refills continuously over the Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Unlimited reports whether the limit lets every request through, as a
+// Limit with no requests does.
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0
+}
+
+// String formats the limit as ParseLimit reads it, such as "60/1m0s".
+func (l Limit) String() string {
+	return strconv.Itoa(l.Requests) + "/" + l.Period.String()
+}
+
+// ParseLimit reads a limit written as requests/period, such as "60/1m".
+// Zero requests, as in "0/1m", mean no limit.
+func ParseLimit(s string) (Limit, error) {
+	requests, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, expected requests/period such as 60/1m", s)
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("invalid number of requests in limit %q", s)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid period in limit %q", s)
+	}
+
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// ParseRouteLimits reads a comma separated list of route limits, each written
+// as "METHOD /path/template=requests/period", such as
+// "POST /users/login=10/1m". The keys of the result are "METHOD /path/template".
+func ParseRouteLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// The template may hold regular expressions, but the limit never has an "="
+		i := strings.LastIndex(entry, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid route limit %q, expected METHOD /path=requests/period", entry)
+		}
+		method, template, ok := strings.Cut(strings.TrimSpace(entry[:i]), " ")
+		if !ok || method != strings.ToUpper(method) || !strings.HasPrefix(strings.TrimSpace(template), "/") {
+			return nil, fmt.Errorf("invalid route %q, expected METHOD /path such as GET /recipes/", entry[:i])
+		}
+
+		limit, err := ParseLimit(entry[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		limits[RouteKey(method, strings.TrimSpace(template))] = limit
+	}
+
+	return limits, nil
+}
+
+// RouteKey returns the key of a route in the route limits.
+func RouteKey(method string, template string) string {
+	return method + " " + template
+}
+
+// Classes of requests with limits of their own.
+const (
+	ClassRead   = "read"
+	ClassWrite  = "write"
+	ClassSearch = "search"
+	// ClassAuthFailures counts the requests with rejected credentials, per
+	// IP address rather than per client.
+	ClassAuthFailures = "auth_failures"
+	// ClassIP counts every request of an IP address, before the caller is
+	// authenticated.
+	ClassIP = "ip"
+)
+
+// Limits are the limits of each class of requests, and of the routes with
+// limits of their own, which take precedence.
+type Limits struct {
+	Read   Limit
+	Write  Limit
+	Search Limit
+	// Routes holds the limits of single routes, by RouteKey.
+	Routes map[string]Limit
+	// AuthFailures limits the rejected credentials of an IP address, so
+	// that keys and tokens cannot be guessed at the pace of the other limits.
+	AuthFailures Limit
+	// IP limits the requests of an IP address before they are authenticated,
+	// so that looking credentials up cannot be done at any pace.
+	IP Limit
+}
+
+// Result is the state of a bucket after a request took a token from it.
+type Result struct {
+	// Allowed reports whether the request may go on.
+	Allowed bool
+	// Remaining is how many more requests the bucket lets through now.
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, when this
+	// one was not.
+	RetryAfter time.Duration
+}
+
+// Store keeps the token buckets of the clients.
+type Store interface {
+	// Take takes a token from the bucket with the key, which refills as set
+	// by limit, and reports whether there was one.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Peek reports whether the bucket with the key has a token left, as Take
+	// would, without taking it.
+	Peek(ctx context.Context, key string, limit Limit) (Result, error)
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		s     string
+		want  Limit
+		fails bool
+	}{
+		{"60/1m", Limit{Requests: 60, Period: time.Minute}, false},
+		{" 10/30s ", Limit{Requests: 10, Period: 30 * time.Second}, false},
+		{"0/1m", Limit{Requests: 0, Period: time.Minute}, false},
+		{"60", Limit{}, true},
+		{"-1/1m", Limit{}, true},
+		{"many/1m", Limit{}, true},
+		{"60/0s", Limit{}, true},
+		{"60/minute", Limit{}, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseLimit(test.s)
+		if (err != nil) != test.fails || got != test.want {
+			t.Errorf("ParseLimit(%q) = %v, %v, want %v, failure %t", test.s, got, err, test.want, test.fails)
+		}
+	}
+
+	if limit := (Limit{Requests: 60, Period: time.Minute}); limit.String() != "60/1m0s" {
+		t.Errorf("String = %q, want %q", limit.String(), "60/1m0s")
+	}
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	tests := []struct {
+		s     string
+		want  map[string]Limit
+		fails bool
+	}{
+		{"", map[string]Limit{}, false},
+		{"POST /users/login=10/1m", map[string]Limit{"POST /users/login": {10, time.Minute}}, false},
+		{
+			"POST /users/login=10/1m, GET /recipe/{id:[0-9]+}=100/1m,",
+			map[string]Limit{"POST /users/login": {10, time.Minute}, "GET /recipe/{id:[0-9]+}": {100, time.Minute}},
+			false,
+		},
+		{"POST /users/login", nil, true},
+		{"post /users/login=10/1m", nil, true},
+		{"POST users/login=10/1m", nil, true},
+		{"/users/login=10/1m", nil, true},
+		{"POST /users/login=10", nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseRouteLimits(test.s)
+		if (err != nil) != test.fails || !maps.Equal(got, test.want) {
+			t.Errorf("ParseRouteLimits(%q) = %v, %v, want %v, failure %t", test.s, got, err, test.want, test.fails)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package router
 
 import (
+	"net/http"
+
 	"github.com/gorilla/mux"
 	"github.com/keevferreira/recipes-api/internal/api"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/models"
 	"github.com/keevferreira/recipes-api/internal/router/routes"
 )
 
@@ -11,12 +15,37 @@ func CreateNewRouter() *mux.Router {
 	return mux.NewRouter()
 }
 
-// ConfigureRoutes configura todas as rotas da API.
-func ConfigureRoutes(routerControler *mux.Router) {
-	//Middleware
-	routerControler.Use(api.LoggingMiddleware)
-	//Rotas
-	routes.RecipesConfigureRoutes(routerControler)
-	routes.IngredientsConfigureRoutes(routerControler)
-	routes.CategoryConfigureRoutes(routerControler)
+// ConfigureRoutes configura todas as rotas da API usando os repositórios informados,
+// além dos endpoints de saúde servidos por healthHandler e do cadastro e login servidos
+// por userHandler. As rotas da API e o /status passam por authMiddleware; as demais de
+// saúde, as de métricas, de cadastro e de login ficam públicas. As rotas da API, de
+// cadastro e de login passam também por rateLimitMiddleware, e as da API antes por
+// ipRateLimitMiddleware.
+func ConfigureRoutes(routerControler *mux.Router, repositories *models.Repositories, healthHandler *handlers.HealthHandler, userHandler *handlers.UserHandler, ipRateLimitMiddleware mux.MiddlewareFunc, authMiddleware mux.MiddlewareFunc, rateLimitMiddleware mux.MiddlewareFunc) {
+	//Middleware: o ID da requisição e o span vêm primeiro para constar em todos os logs, e a
+	//recuperação de panics fica depois do log de acesso e das métricas para que eles registrem o 500
+	routerControler.Use(api.RequestIDMiddleware, api.TracingMiddleware, api.LoggingMiddleware, api.MetricsMiddleware, api.RecoveryMiddleware)
+	routerControler.NotFoundHandler = api.RequestIDMiddleware(api.TracingMiddleware(api.LoggingMiddleware(api.MetricsMiddleware(http.HandlerFunc(handlers.NotFound)))))
+	//Rotas públicas, registradas antes do sub-roteador da API para que casem primeiro
+	routes.HealthConfigureRoutes(routerControler, healthHandler)
+	routes.MetricsConfigureRoutes(routerControler)
+	//Cadastro e login: públicos, mas limitados por IP contra tentativas de senha em massa
+	usersRouter := routerControler.NewRoute().Subrouter()
+	usersRouter.Use(rateLimitMiddleware)
+	routes.UsersConfigureRoutes(usersRouter, userHandler)
+	//Rotas da API: o limite por IP vem primeiro, para que nenhum IP faça o banco consultar
+	//credenciais mais rápido do que ele permite; depois vem a autenticação, que limita por IP as
+	//credenciais recusadas, o limite de requisições contado por cliente, agora identificado, e a
+	//leitura dos parâmetros do caminho. Cada rota confere em seguida a permissão que exige do
+	//papel de quem fez a requisição
+	apiRouter := routerControler.NewRoute().Subrouter()
+	apiRouter.Use(ipRateLimitMiddleware, authMiddleware, rateLimitMiddleware, handlers.PathParamsMiddleware)
+	routes.RecipesConfigureRoutes(apiRouter, repositories)
+	routes.IngredientsConfigureRoutes(apiRouter, repositories.Ingredients)
+	routes.CategoryConfigureRoutes(apiRouter, repositories.Categories)
+	routes.ConversionConfigureRoutes(apiRouter, repositories.Ingredients)
+	routes.ShoppingListsConfigureRoutes(apiRouter, repositories)
+	routes.PantryConfigureRoutes(apiRouter, repositories.Pantry)
+	routes.UsersAdminConfigureRoutes(apiRouter, userHandler)
+	routes.StatusConfigureRoutes(apiRouter, healthHandler)
 }
@@ -3,31 +3,33 @@ package routes
 import (
 	"github.com/gorilla/mux"
 	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
 )
 
-func CategoryConfigureRoutes(Router *mux.Router) {
-	categoryHandler := handlers.NewCategoryHandler()
+func CategoryConfigureRoutes(Router *mux.Router, categories models.CategoryRepository) {
+	categoryHandler := handlers.NewCategoryHandler(categories)
 
 	/**
-	ENDPOINTS /category/{id} ROUTES
+	ENDPOINTS /category/{id:[0-9]+} ROUTES
 	**/
 
 	// Roteamento para a função GetCategoryByID quando a solicitação é um método GET
-	Router.HandleFunc("/category/{id}", categoryHandler.GetCategoryByID).Methods("GET")
+	Router.Handle("/category/{id:[0-9]+}", require(auth.PermCatalogRead, categoryHandler.GetCategoryByID)).Methods("GET")
 
 	// Roteamento para a função UpdateCategoryByID quando a solicitação é um método PUT
-	Router.HandleFunc("/category/{id}", categoryHandler.UpdateCategoryByID).Methods("PUT")
+	Router.Handle("/category/{id:[0-9]+}", require(auth.PermCatalogManage, categoryHandler.UpdateCategoryByID)).Methods("PUT")
 
 	// Roteamento para a função DeleteCategoryByID quando a solicitação é um método DELETE
-	Router.HandleFunc("/category/{id}", categoryHandler.DeleteCategoryByID).Methods("DELETE")
+	Router.Handle("/category/{id:[0-9]+}", require(auth.PermCatalogManage, categoryHandler.DeleteCategoryByID)).Methods("DELETE")
 
 	/**
 	ENDPOINTS /categories/ ROUTES
 	**/
 
 	// Roteamento para a função GetCategories quando a solicitação é um método GET
-	Router.HandleFunc("/categories/", categoryHandler.GetCategories).Methods("GET")
+	Router.Handle("/categories/", require(auth.PermCatalogRead, categoryHandler.GetCategories)).Methods("GET")
 
 	// Roteamento para a função CreateCategory quando a solicitação é um método POST
-	Router.HandleFunc("/categories/", categoryHandler.CreateCategory).Methods("POST")
+	Router.Handle("/categories/", require(auth.PermCatalogCreate, categoryHandler.CreateCategory)).Methods("POST")
 }
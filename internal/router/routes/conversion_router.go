@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+func ConversionConfigureRoutes(Router *mux.Router, ingredients models.IngredientRepository) {
+	conversionHandler := handlers.NewConversionHandler(ingredients)
+
+	/**
+	ENDPOINTS /convert ROUTES
+	**/
+
+	// Roteamento para a função Convert quando a solicitação é um método POST
+	Router.Handle("/convert", require(auth.PermCatalogRead, conversionHandler.Convert)).Methods("POST")
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+)
+
+func HealthConfigureRoutes(Router *mux.Router, healthHandler *handlers.HealthHandler) {
+	/**
+	ENDPOINTS DE SAÚDE
+	**/
+
+	// Roteamento para a função Healthz (o processo está de pé) quando a solicitação é um método GET
+	Router.HandleFunc("/healthz", healthHandler.Healthz).Methods("GET")
+
+	// Roteamento para a função Readyz (o serviço pode receber tráfego) quando a solicitação é um método GET
+	Router.HandleFunc("/readyz", healthHandler.Readyz).Methods("GET")
+}
+
+// StatusConfigureRoutes registra o relatório detalhado do serviço, destinado a quem opera a
+// API, entre as rotas autenticadas e restrito aos papéis com permissão de ler o status.
+func StatusConfigureRoutes(Router *mux.Router, healthHandler *handlers.HealthHandler) {
+	// Roteamento para a função Status quando a solicitação é um método GET
+	Router.Handle("/status", require(auth.PermStatusRead, healthHandler.Status)).Methods("GET")
+}
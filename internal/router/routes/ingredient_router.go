@@ -3,31 +3,37 @@ package routes
 import (
 	"github.com/gorilla/mux"
 	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
 )
 
-func IngredientsConfigureRoutes(Router *mux.Router) {
-	ingredientHandler := handlers.NewIngredientHandler()
+func IngredientsConfigureRoutes(Router *mux.Router, ingredients models.IngredientRepository) {
+	ingredientHandler := handlers.NewIngredientHandler(ingredients)
 
 	/**
-	ENDPOINTS /ingredient/{id} ROUTES
+	ENDPOINTS /ingredient/{id:[0-9]+} ROUTES
 	**/
 
 	// Roteamento para a função GetIngredientByID quando a solicitação é um método GET
-	Router.HandleFunc("/ingredient/{id}", ingredientHandler.GetIngredientByID).Methods("GET")
+	Router.Handle("/ingredient/{id:[0-9]+}", require(auth.PermCatalogRead, ingredientHandler.GetIngredientByID)).Methods("GET")
 
 	// Roteamento para a função UpdateIngredientByID quando a solicitação é um método PUT
-	Router.HandleFunc("/ingredient/{id}", ingredientHandler.UpdateIngredientByID).Methods("PUT")
+	Router.Handle("/ingredient/{id:[0-9]+}", require(auth.PermCatalogManage, ingredientHandler.UpdateIngredientByID)).Methods("PUT")
 
 	// Roteamento para a função DeleteIngredientByID quando a solicitação é um método DELETE
-	Router.HandleFunc("/ingredient/{id}", ingredientHandler.DeleteIngredientByID).Methods("DELETE")
+	Router.Handle("/ingredient/{id:[0-9]+}", require(auth.PermCatalogManage, ingredientHandler.DeleteIngredientByID)).Methods("DELETE")
 
 	/**
 	ENDPOINTS /ingredients/ ROUTES
 	**/
 
 	// Roteamento para a função GetIngredients quando a solicitação é um método GET
-	Router.HandleFunc("/igredients/", ingredientHandler.GetIngredients).Methods("GET")
+	Router.Handle("/igredients/", require(auth.PermCatalogRead, ingredientHandler.GetIngredients)).Methods("GET")
 
 	// Roteamento para a função CreateIngredient quando a solicitação é um método POST
-	Router.HandleFunc("/igredients/", ingredientHandler.CreateIngredient).Methods("POST")
+	Router.Handle("/igredients/", require(auth.PermCatalogCreate, ingredientHandler.CreateIngredient)).Methods("POST")
+
+	// Os caminhos /ingredients/ são a grafia correta; /igredients/ continua disponível para os clientes existentes
+	Router.Handle("/ingredients/", require(auth.PermCatalogRead, ingredientHandler.GetIngredients)).Methods("GET")
+	Router.Handle("/ingredients/", require(auth.PermCatalogCreate, ingredientHandler.CreateIngredient)).Methods("POST")
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/metrics"
+)
+
+// MetricsConfigureRoutes registra as métricas do Prometheus, públicas para que os coletores
+// não precisem de credenciais. Elas incluem as estatísticas do pool de conexões, então o
+// /metrics deve ficar acessível só pela rede interna.
+func MetricsConfigureRoutes(Router *mux.Router) {
+	/**
+	ENDPOINT DE MÉTRICAS
+	**/
+
+	// Roteamento para as métricas no formato texto do Prometheus quando a solicitação é um método GET
+	Router.Handle("/metrics", metrics.Handler()).Methods("GET")
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+func PantryConfigureRoutes(Router *mux.Router, pantry models.PantryRepository) {
+	pantryHandler := handlers.NewPantryHandler(pantry)
+
+	// Cada principal tem a sua própria despensa, e as rotas abaixo só enxergam a de
+	// quem fez a requisição.
+
+	/**
+	ENDPOINTS /pantry/{ingredient_id:[0-9]+} ROUTES
+	**/
+
+	// Roteamento para a função SetPantryItem quando a solicitação é um método PUT
+	Router.Handle("/pantry/{ingredient_id:[0-9]+}", require(auth.PermPlanningWrite, pantryHandler.SetPantryItem)).Methods("PUT")
+
+	// Roteamento para a função DeletePantryItem quando a solicitação é um método DELETE
+	Router.Handle("/pantry/{ingredient_id:[0-9]+}", require(auth.PermPlanningWrite, pantryHandler.DeletePantryItem)).Methods("DELETE")
+
+	/**
+	ENDPOINTS /pantry/ ROUTES
+	**/
+
+	// Roteamento para a função GetPantryItems quando a solicitação é um método GET
+	Router.Handle("/pantry/", require(auth.PermPlanningRead, pantryHandler.GetPantryItems)).Methods("GET")
+
+	/**
+	ENDPOINTS /recipes/cookable ROUTES
+	**/
+
+	// Roteamento para a função GetCookableRecipes quando a solicitação é um método GET
+	Router.Handle("/recipes/cookable", require(auth.PermPlanningRead, pantryHandler.GetCookableRecipes)).Methods("GET")
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/keevferreira/recipes-api/internal/api"
+	"github.com/keevferreira/recipes-api/internal/auth"
+)
+
+// require envolve o handler no middleware que exige a permissão informada, para que cada
+// rota da API declare a permissão dela ao ser registrada.
+func require(permission auth.Permission, handler http.HandlerFunc) http.Handler {
+	return api.RequirePermission(permission)(handler)
+}
@@ -3,31 +3,53 @@ package routes
 import (
 	"github.com/gorilla/mux"
 	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
 )
 
-func RecipesConfigureRoutes(Router *mux.Router) {
-	recipeHandler := handlers.NewRecipeHandler()
+func RecipesConfigureRoutes(Router *mux.Router, repositories *models.Repositories) {
+	recipeHandler := handlers.NewRecipeHandler(repositories)
 
 	/**
-	ENDPOINTS /recipe/{id} ROUTES
+	ENDPOINTS /recipe/{id:[0-9]+} ROUTES
 	**/
 
 	// Roteamento para a função GetRecipeByID quando a solicitação é um método GET
-	Router.HandleFunc("/recipe/{id}", recipeHandler.GetRecipeByID).Methods("GET")
+	Router.Handle("/recipe/{id:[0-9]+}", require(auth.PermRecipesRead, recipeHandler.GetRecipeByID)).Methods("GET")
 
 	// Roteamento para a função UpdateRecipeByID quando a solicitação é um método PUT
-	Router.HandleFunc("/recipe/{id}", recipeHandler.UpdateRecipeByID).Methods("PUT")
+	Router.Handle("/recipe/{id:[0-9]+}", require(auth.PermRecipesWrite, recipeHandler.UpdateRecipeByID)).Methods("PUT")
 
 	// Roteamento para a função DeleteRecipeByID quando a solicitação é um método DELETE
-	Router.HandleFunc("/recipe/{id}", recipeHandler.DeleteRecipeByID).Methods("DELETE")
+	Router.Handle("/recipe/{id:[0-9]+}", require(auth.PermRecipesWrite, recipeHandler.DeleteRecipeByID)).Methods("DELETE")
+
+	// Roteamento para a função GetScaledRecipe quando a solicitação é um método GET
+	Router.Handle("/recipe/{id:[0-9]+}/scaled", require(auth.PermRecipesRead, recipeHandler.GetScaledRecipe)).Methods("GET")
+
+	// Roteamento para a função ReorderRecipeSteps quando a solicitação é um método PUT
+	Router.Handle("/recipe/{id:[0-9]+}/steps/order", require(auth.PermRecipesWrite, recipeHandler.ReorderRecipeSteps)).Methods("PUT")
 
 	/**
 	ENDPOINTS /recipes/ ROUTES
 	**/
 
 	// Roteamento para a função GetRecipes quando a solicitação é um método GET
-	Router.HandleFunc("/recipes/", recipeHandler.GetRecipes).Methods("GET")
+	Router.Handle("/recipes/", require(auth.PermRecipesRead, recipeHandler.GetRecipes)).Methods("GET")
 
 	// Roteamento para a função CreateRecipe quando a solicitação é um método POST
-	Router.HandleFunc("/recipes/", recipeHandler.CreateRecipe).Methods("POST")
+	Router.Handle("/recipes/", require(auth.PermRecipesWrite, recipeHandler.CreateRecipe)).Methods("POST")
+
+	/**
+	ENDPOINTS /recipes/search ROUTES
+	**/
+
+	// Roteamento para a função SearchRecipes quando a solicitação é um método GET
+	Router.Handle("/recipes/search", require(auth.PermRecipesRead, recipeHandler.SearchRecipes)).Methods("GET")
+
+	/**
+	ENDPOINTS /users/{id:[0-9]+}/recipes ROUTES
+	**/
+
+	// Roteamento para a função GetRecipesByUserID quando a solicitação é um método GET
+	Router.Handle("/users/{id:[0-9]+}/recipes", require(auth.PermRecipesRead, recipeHandler.GetRecipesByUserID)).Methods("GET")
 }
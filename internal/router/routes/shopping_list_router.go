@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+func ShoppingListsConfigureRoutes(Router *mux.Router, repositories *models.Repositories) {
+	shoppingListHandler := handlers.NewShoppingListHandler(repositories)
+
+	// Cada lista pertence a quem a criou. Além da permissão de cada rota, o handler
+	// só dá acesso a uma lista ao dono dela ou a quem tem a permissão planning:manage.
+
+	/**
+	ENDPOINTS /shopping-lists/{id:[0-9]+} ROUTES
+	**/
+
+	// Roteamento para a função GetShoppingListByID quando a solicitação é um método GET
+	Router.Handle("/shopping-lists/{id:[0-9]+}", require(auth.PermPlanningRead, shoppingListHandler.GetShoppingListByID)).Methods("GET")
+
+	// Roteamento para a função DeleteShoppingListByID quando a solicitação é um método DELETE
+	Router.Handle("/shopping-lists/{id:[0-9]+}", require(auth.PermPlanningWrite, shoppingListHandler.DeleteShoppingListByID)).Methods("DELETE")
+
+	// Roteamento para a função CheckShoppingListItem quando a solicitação é um método PATCH
+	Router.Handle("/shopping-lists/{id:[0-9]+}/items/{item_id:[0-9]+}", require(auth.PermPlanningWrite, shoppingListHandler.CheckShoppingListItem)).Methods("PATCH")
+
+	/**
+	ENDPOINTS /shopping-lists ROUTES
+	**/
+
+	// Roteamento para a função CreateShoppingList quando a solicitação é um método POST
+	Router.Handle("/shopping-lists", require(auth.PermPlanningWrite, shoppingListHandler.CreateShoppingList)).Methods("POST")
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/keevferreira/recipes-api/internal/api/handlers"
+	"github.com/keevferreira/recipes-api/internal/auth"
+)
+
+// UsersConfigureRoutes registra as rotas públicas de cadastro e de login.
+func UsersConfigureRoutes(Router *mux.Router, userHandler *handlers.UserHandler) {
+	/**
+	ENDPOINTS /users/ ROUTES
+	**/
+
+	// Roteamento para a função Register quando a solicitação é um método POST
+	Router.HandleFunc("/users/", userHandler.Register).Methods("POST")
+
+	// Roteamento para a função Login quando a solicitação é um método POST
+	Router.HandleFunc("/users/login", userHandler.Login).Methods("POST")
+}
+
+// UsersAdminConfigureRoutes registra as rotas de administração dos usuários, que exigem autenticação.
+func UsersAdminConfigureRoutes(Router *mux.Router, userHandler *handlers.UserHandler) {
+	/**
+	ENDPOINTS /users/{id:[0-9]+} ROUTES
+	**/
+
+	// Roteamento para a função GetUserByID quando a solicitação é um método GET
+	Router.Handle("/users/{id:[0-9]+}", require(auth.PermUsersManage, userHandler.GetUserByID)).Methods("GET")
+
+	// Roteamento para a função SetUserRole quando a solicitação é um método PUT
+	Router.Handle("/users/{id:[0-9]+}/role", require(auth.PermUsersManage, userHandler.SetUserRole)).Methods("PUT")
+}
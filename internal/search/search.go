@@ -0,0 +1,193 @@
+// Package search holds the text handling shared by the recipe search of the
+// backends without a full-text engine of their own: accent folding, a light
+// Portuguese stemmer and snippet highlighting.
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Highlight tags wrapped around the matched words of a snippet.
+const (
+	StartSel = "<mark>"
+	StopSel  = "</mark>"
+)
+
+// Control characters delimiting the matched words of a snippet before it is
+// escaped by Highlight, so the storage engines never mix markup with text.
+const (
+	StartMark = "\x02"
+	StopMark  = "\x03"
+)
+
+var marks = strings.NewReplacer(StartMark, StartSel, StopMark, StopSel)
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// stopwords are common Portuguese words left out of queries.
+var stopwords = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "e": true, "de": true,
+	"da": true, "do": true, "das": true, "dos": true, "em": true, "na": true,
+	"no": true, "nas": true, "nos": true, "com": true, "sem": true, "para": true,
+	"por": true, "um": true, "uma": true, "ao": true, "aos": true,
+}
+
+// Normalize lower cases text and strips its accents.
+func Normalize(text string) string {
+	return accents.Replace(strings.ToLower(text))
+}
+
+// Words splits text into its words, keeping letters and digits.
+func Words(text string) []string {
+	return strings.FieldsFunc(text, isPunct)
+}
+
+// Query is a parsed search query. A word matches a term when its normalized
+// form starts with it.
+type Query struct {
+	// Terms must all be found in a document.
+	Terms []string
+	// Excluded are the terms of the words prefixed with "-", which must not
+	// be found in a document.
+	Excluded []string
+}
+
+// Parse splits a query into the stems of its words, without stopwords and
+// duplicates, following the "-word" syntax of web search engines.
+func Parse(query string) Query {
+	var parsed Query
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(Normalize(query)) {
+		excluded := strings.HasPrefix(field, "-")
+		for _, word := range Words(field) {
+			if stopwords[word] {
+				continue
+			}
+
+			term := Stem(word)
+			if seen[term] {
+				continue
+			}
+			seen[term] = true
+
+			if excluded {
+				parsed.Excluded = append(parsed.Excluded, term)
+			} else {
+				parsed.Terms = append(parsed.Terms, term)
+			}
+		}
+	}
+
+	return parsed
+}
+
+// pluralSuffixes maps Portuguese plural endings, without accents, to their
+// singular form.
+var pluralSuffixes = []struct{ plural, singular string }{
+	{"oes", "ao"}, {"aes", "ao"}, {"ais", "al"}, {"eis", "el"}, {"ois", "ol"},
+	{"ns", "m"}, {"res", "r"}, {"zes", "z"}, {"s", ""},
+}
+
+// Stem reduces a normalized word to a stem shared by its plural and gendered
+// forms, so "assadas", "assado" and "assados" all become "assad". It is much
+// lighter than a real stemmer and meant to be matched as a prefix.
+func Stem(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+
+	for _, suffix := range pluralSuffixes {
+		if strings.HasSuffix(word, suffix.plural) {
+			word = strings.TrimSuffix(word, suffix.plural) + suffix.singular
+			break
+		}
+	}
+
+	if len(word) > 4 && strings.ContainsAny(word[len(word)-1:], "aeo") {
+		word = word[:len(word)-1]
+	}
+
+	return word
+}
+
+// Matches reports whether a word of a document matches any of terms.
+func Matches(word string, terms []string) bool {
+	word = Normalize(word)
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Count returns how many words of text match any of terms.
+func Count(text string, terms []string) int {
+	count := 0
+	for _, word := range Words(text) {
+		if Matches(word, terms) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Highlight HTML escapes snippet and turns its StartMark and StopMark into
+// the StartSel and StopSel tags.
+func Highlight(snippet string) string {
+	return marks.Replace(html.EscapeString(snippet))
+}
+
+// Snippet returns an excerpt of about size words of text around its first
+// match, HTML escaped and with the matched words wrapped in StartSel and
+// StopSel. Text without a match yields its first words.
+func Snippet(text string, terms []string, size int) string {
+	words := strings.Fields(text)
+
+	first := 0
+	for i, word := range words {
+		if Matches(strings.TrimFunc(word, isPunct), terms) {
+			first = i
+			break
+		}
+	}
+
+	start := max(first-size/3, 0)
+	end := min(start+size, len(words))
+
+	excerpt := make([]string, 0, end-start)
+	for _, word := range words[start:end] {
+		core := strings.TrimFunc(word, isPunct)
+		if core != "" && Matches(core, terms) {
+			i := strings.Index(word, core)
+			word = word[:i] + StartMark + core + StopMark + word[i+len(core):]
+		}
+		excerpt = append(excerpt, word)
+	}
+
+	snippet := strings.Join(excerpt, " ")
+	if start > 0 {
+		snippet = "… " + snippet
+	}
+	if end < len(words) {
+		snippet += " …"
+	}
+
+	return Highlight(snippet)
+}
+
+// isPunct reports whether r separates words.
+func isPunct(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query    string
+		terms    []string
+		excluded []string
+	}{
+		{"bolo", []string{"bolo"}, nil},
+		{"Pães de queijo", []string{"pao", "queij"}, nil},
+		{"Feijões com arroz", []string{"feija", "arroz"}, nil},
+		{"bolo bolos BOLO", []string{"bolo"}, nil},
+		{"ovos leite -pudim", []string{"ovo", "leit"}, []string{"pudim"}},
+		{"-assados frango", []string{"frang"}, []string{"assad"}},
+		{"frango, batata!", []string{"frang", "batat"}, nil},
+		{"de da do", nil, nil},
+		{"", nil, nil},
+	}
+
+	for _, test := range tests {
+		got := Parse(test.query)
+		if !slices.Equal(got.Terms, test.terms) || !slices.Equal(got.Excluded, test.excluded) {
+			t.Errorf("Parse(%q) = %+v, want terms %v and excluded %v", test.query, got, test.terms, test.excluded)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"assadas", "assad"},
+		{"assado", "assad"},
+		{"assados", "assad"},
+		{"paes", "pao"},
+		{"pao", "pao"},
+		{"pasteis", "pastel"},
+		{"animais", "animal"},
+		{"flores", "flor"},
+		{"sal", "sal"},
+		{"bolo", "bolo"},
+	}
+
+	for _, test := range tests {
+		if got := Stem(test.word); got != test.want {
+			t.Errorf("Stem(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	if got := Count("Ovos, ovos e mais OVOS.", Parse("ovo").Terms); got != 3 {
+		t.Errorf("Count = %d, want 3", got)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		size  int
+		want  string
+	}{
+		{
+			name:  "match",
+			text:  "Misture os ovos, e asse.",
+			query: "ovo",
+			size:  16,
+			want:  "Misture os <mark>ovos</mark>, e asse.",
+		},
+		{
+			name:  "accents",
+			text:  "Pão com manteiga",
+			query: "pao",
+			size:  16,
+			want:  "<mark>Pão</mark> com manteiga",
+		},
+		{
+			name:  "excerpt",
+			text:  "um dois tres quatro cinco seis sete oito nove dez ovos onze doze treze catorze quinze",
+			query: "ovos",
+			size:  6,
+			want:  "… nove dez <mark>ovos</mark> onze doze treze …",
+		},
+		{
+			name:  "no match",
+			text:  "sem resultado aqui",
+			query: "ovos",
+			size:  2,
+			want:  "sem resultado …",
+		},
+		{
+			name:  "markup is escaped",
+			text:  `Bata os ovos <script>alert("x")</script> & sirva.`,
+			query: "ovos",
+			size:  16,
+			want:  "Bata os <mark>ovos</mark> &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; sirva.",
+		},
+		{
+			name:  "quotes around a match are escaped",
+			text:  `"ovos" & 'leite'`,
+			query: "ovos leite",
+			size:  16,
+			want:  "&#34;<mark>ovos</mark>&#34; &amp; &#39;<mark>leite</mark>&#39;",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Snippet(test.text, Parse(test.query).Terms, test.size)
+			if got != test.want {
+				t.Errorf("Snippet = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := Highlight("a " + StartMark + "b&c" + StopMark + " <d>")
+	want := "a <mark>b&amp;c</mark> &lt;d&gt;"
+	if got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+}
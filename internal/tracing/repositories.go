@@ -0,0 +1,368 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentRepositories wraps every repository so that each call of its
+// methods is a span, named after the interface and the method, such as
+// "RecipeRepository.GetRecipeByID". The spans of the SQL statements run by
+// the call are its children.
+func InstrumentRepositories(repositories *models.Repositories) *models.Repositories {
+	return &models.Repositories{
+		Recipes:       &recipeRepository{next: repositories.Recipes},
+		Ingredients:   &ingredientRepository{next: repositories.Ingredients},
+		Categories:    &categoryRepository{next: repositories.Categories},
+		ShoppingLists: &shoppingListRepository{next: repositories.ShoppingLists},
+		Pantry:        &pantryRepository{next: repositories.Pantry},
+		APIKeys:       &apiKeyRepository{next: repositories.APIKeys},
+		Users:         &userRepository{next: repositories.Users},
+	}
+}
+
+// start starts the span of a repository method as a child of the span in ctx.
+func start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal))
+}
+
+// end records err, if any, on span and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// recipeRepository instruments a models.RecipeRepository.
+type recipeRepository struct {
+	next models.RecipeRepository
+}
+
+func (r *recipeRepository) GetRecipeByID(ctx context.Context, id int) (models.Recipe, error) {
+	ctx, span := start(ctx, "RecipeRepository.GetRecipeByID")
+	result, err := r.next.GetRecipeByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *recipeRepository) GetAllRecipes(ctx context.Context, filter models.RecipeFilter, includes models.RecipeIncludes, options models.ListOptions) ([]models.Recipe, models.PageInfo, error) {
+	ctx, span := start(ctx, "RecipeRepository.GetAllRecipes")
+	items, info, err := r.next.GetAllRecipes(ctx, filter, includes, options)
+	end(span, err)
+	return items, info, err
+}
+
+func (r *recipeRepository) CreateRecipe(ctx context.Context, recipe models.Recipe) (int, error) {
+	ctx, span := start(ctx, "RecipeRepository.CreateRecipe")
+	result, err := r.next.CreateRecipe(ctx, recipe)
+	end(span, err)
+	return result, err
+}
+
+func (r *recipeRepository) UpdateRecipeByID(ctx context.Context, id int, updatedRecipe models.Recipe) error {
+	ctx, span := start(ctx, "RecipeRepository.UpdateRecipeByID")
+	err := r.next.UpdateRecipeByID(ctx, id, updatedRecipe)
+	end(span, err)
+	return err
+}
+
+func (r *recipeRepository) DeleteRecipeByID(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "RecipeRepository.DeleteRecipeByID")
+	err := r.next.DeleteRecipeByID(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *recipeRepository) SearchRecipes(ctx context.Context, query string, options models.ListOptions) ([]models.RecipeSearchResult, models.PageInfo, error) {
+	ctx, span := start(ctx, "RecipeRepository.SearchRecipes")
+	items, info, err := r.next.SearchRecipes(ctx, query, options)
+	end(span, err)
+	return items, info, err
+}
+
+func (r *recipeRepository) GetStepsByRecipeID(ctx context.Context, recipeID int) ([]models.Step, error) {
+	ctx, span := start(ctx, "RecipeRepository.GetStepsByRecipeID")
+	result, err := r.next.GetStepsByRecipeID(ctx, recipeID)
+	end(span, err)
+	return result, err
+}
+
+func (r *recipeRepository) ReorderStepsByRecipeID(ctx context.Context, recipeID int, stepIDs []int) error {
+	ctx, span := start(ctx, "RecipeRepository.ReorderStepsByRecipeID")
+	err := r.next.ReorderStepsByRecipeID(ctx, recipeID, stepIDs)
+	end(span, err)
+	return err
+}
+
+// ingredientRepository instruments a models.IngredientRepository.
+type ingredientRepository struct {
+	next models.IngredientRepository
+}
+
+func (r *ingredientRepository) GetIngredientByID(ctx context.Context, id int) (models.Ingredient, error) {
+	ctx, span := start(ctx, "IngredientRepository.GetIngredientByID")
+	result, err := r.next.GetIngredientByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *ingredientRepository) GetIngredientsByIDs(ctx context.Context, ids []int) ([]models.Ingredient, error) {
+	ctx, span := start(ctx, "IngredientRepository.GetIngredientsByIDs")
+	result, err := r.next.GetIngredientsByIDs(ctx, ids)
+	end(span, err)
+	return result, err
+}
+
+func (r *ingredientRepository) GetAllIngredients(ctx context.Context, filter models.IngredientFilter, options models.ListOptions) ([]models.Ingredient, models.PageInfo, error) {
+	ctx, span := start(ctx, "IngredientRepository.GetAllIngredients")
+	items, info, err := r.next.GetAllIngredients(ctx, filter, options)
+	end(span, err)
+	return items, info, err
+}
+
+func (r *ingredientRepository) CreateIngredient(ctx context.Context, ingredient models.Ingredient) (int, error) {
+	ctx, span := start(ctx, "IngredientRepository.CreateIngredient")
+	result, err := r.next.CreateIngredient(ctx, ingredient)
+	end(span, err)
+	return result, err
+}
+
+func (r *ingredientRepository) UpdateIngredientByID(ctx context.Context, id int, updatedIngredient models.Ingredient) error {
+	ctx, span := start(ctx, "IngredientRepository.UpdateIngredientByID")
+	err := r.next.UpdateIngredientByID(ctx, id, updatedIngredient)
+	end(span, err)
+	return err
+}
+
+func (r *ingredientRepository) DeleteIngredientByID(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "IngredientRepository.DeleteIngredientByID")
+	err := r.next.DeleteIngredientByID(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *ingredientRepository) GetIngredientsByRecipeID(ctx context.Context, recipeID int) ([]models.RecipeIngredient, error) {
+	ctx, span := start(ctx, "IngredientRepository.GetIngredientsByRecipeID")
+	result, err := r.next.GetIngredientsByRecipeID(ctx, recipeID)
+	end(span, err)
+	return result, err
+}
+
+func (r *ingredientRepository) UpdateIngredientsByRecipeID(ctx context.Context, recipeID int, updatedIngredients []models.RecipeIngredient) error {
+	ctx, span := start(ctx, "IngredientRepository.UpdateIngredientsByRecipeID")
+	err := r.next.UpdateIngredientsByRecipeID(ctx, recipeID, updatedIngredients)
+	end(span, err)
+	return err
+}
+
+func (r *ingredientRepository) DeleteIngredientsByRecipeID(ctx context.Context, recipeID int) error {
+	ctx, span := start(ctx, "IngredientRepository.DeleteIngredientsByRecipeID")
+	err := r.next.DeleteIngredientsByRecipeID(ctx, recipeID)
+	end(span, err)
+	return err
+}
+
+// categoryRepository instruments a models.CategoryRepository.
+type categoryRepository struct {
+	next models.CategoryRepository
+}
+
+func (r *categoryRepository) GetCategoryByID(ctx context.Context, id int) (models.Category, error) {
+	ctx, span := start(ctx, "CategoryRepository.GetCategoryByID")
+	result, err := r.next.GetCategoryByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *categoryRepository) GetCategoriesByIDs(ctx context.Context, ids []int) ([]models.Category, error) {
+	ctx, span := start(ctx, "CategoryRepository.GetCategoriesByIDs")
+	result, err := r.next.GetCategoriesByIDs(ctx, ids)
+	end(span, err)
+	return result, err
+}
+
+func (r *categoryRepository) GetAllCategories(ctx context.Context, options models.ListOptions) ([]models.Category, models.PageInfo, error) {
+	ctx, span := start(ctx, "CategoryRepository.GetAllCategories")
+	items, info, err := r.next.GetAllCategories(ctx, options)
+	end(span, err)
+	return items, info, err
+}
+
+func (r *categoryRepository) CreateCategory(ctx context.Context, category models.Category) (int, error) {
+	ctx, span := start(ctx, "CategoryRepository.CreateCategory")
+	result, err := r.next.CreateCategory(ctx, category)
+	end(span, err)
+	return result, err
+}
+
+func (r *categoryRepository) UpdateCategoryByID(ctx context.Context, id int, updatedCategory models.Category) error {
+	ctx, span := start(ctx, "CategoryRepository.UpdateCategoryByID")
+	err := r.next.UpdateCategoryByID(ctx, id, updatedCategory)
+	end(span, err)
+	return err
+}
+
+func (r *categoryRepository) DeleteCategoryByID(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "CategoryRepository.DeleteCategoryByID")
+	err := r.next.DeleteCategoryByID(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *categoryRepository) GetCategoriesByRecipeID(ctx context.Context, recipeID int) ([]models.Category, error) {
+	ctx, span := start(ctx, "CategoryRepository.GetCategoriesByRecipeID")
+	result, err := r.next.GetCategoriesByRecipeID(ctx, recipeID)
+	end(span, err)
+	return result, err
+}
+
+func (r *categoryRepository) UpdateCategoriesByRecipeID(ctx context.Context, recipeID int, updatedCategories []models.Category) error {
+	ctx, span := start(ctx, "CategoryRepository.UpdateCategoriesByRecipeID")
+	err := r.next.UpdateCategoriesByRecipeID(ctx, recipeID, updatedCategories)
+	end(span, err)
+	return err
+}
+
+func (r *categoryRepository) DeleteCategoryByRecipeID(ctx context.Context, recipeID int) error {
+	ctx, span := start(ctx, "CategoryRepository.DeleteCategoryByRecipeID")
+	err := r.next.DeleteCategoryByRecipeID(ctx, recipeID)
+	end(span, err)
+	return err
+}
+
+// shoppingListRepository instruments a models.ShoppingListRepository.
+type shoppingListRepository struct {
+	next models.ShoppingListRepository
+}
+
+func (r *shoppingListRepository) GetShoppingListByID(ctx context.Context, id int) (models.ShoppingList, error) {
+	ctx, span := start(ctx, "ShoppingListRepository.GetShoppingListByID")
+	result, err := r.next.GetShoppingListByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *shoppingListRepository) CreateShoppingList(ctx context.Context, list models.ShoppingList) (int, error) {
+	ctx, span := start(ctx, "ShoppingListRepository.CreateShoppingList")
+	result, err := r.next.CreateShoppingList(ctx, list)
+	end(span, err)
+	return result, err
+}
+
+func (r *shoppingListRepository) DeleteShoppingListByID(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "ShoppingListRepository.DeleteShoppingListByID")
+	err := r.next.DeleteShoppingListByID(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *shoppingListRepository) CheckShoppingListItem(ctx context.Context, listID int, itemID int, checked bool) error {
+	ctx, span := start(ctx, "ShoppingListRepository.CheckShoppingListItem")
+	err := r.next.CheckShoppingListItem(ctx, listID, itemID, checked)
+	end(span, err)
+	return err
+}
+
+// pantryRepository instruments a models.PantryRepository.
+type pantryRepository struct {
+	next models.PantryRepository
+}
+
+func (r *pantryRepository) GetPantryItems(ctx context.Context, owner string) ([]models.PantryItem, error) {
+	ctx, span := start(ctx, "PantryRepository.GetPantryItems")
+	result, err := r.next.GetPantryItems(ctx, owner)
+	end(span, err)
+	return result, err
+}
+
+func (r *pantryRepository) SetPantryItem(ctx context.Context, owner string, item models.PantryItem) error {
+	ctx, span := start(ctx, "PantryRepository.SetPantryItem")
+	err := r.next.SetPantryItem(ctx, owner, item)
+	end(span, err)
+	return err
+}
+
+func (r *pantryRepository) DeletePantryItem(ctx context.Context, owner string, ingredientID int) error {
+	ctx, span := start(ctx, "PantryRepository.DeletePantryItem")
+	err := r.next.DeletePantryItem(ctx, owner, ingredientID)
+	end(span, err)
+	return err
+}
+
+func (r *pantryRepository) FindCookableRecipes(ctx context.Context, owner string, maxMissing int, limit int) ([]models.CookableRecipe, error) {
+	ctx, span := start(ctx, "PantryRepository.FindCookableRecipes")
+	result, err := r.next.FindCookableRecipes(ctx, owner, maxMissing, limit)
+	end(span, err)
+	return result, err
+}
+
+// apiKeyRepository instruments a models.APIKeyRepository.
+type apiKeyRepository struct {
+	next models.APIKeyRepository
+}
+
+func (r *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (models.APIKey, error) {
+	ctx, span := start(ctx, "APIKeyRepository.GetAPIKeyByHash")
+	result, err := r.next.GetAPIKeyByHash(ctx, hash)
+	end(span, err)
+	return result, err
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key models.APIKey) (int, error) {
+	ctx, span := start(ctx, "APIKeyRepository.CreateAPIKey")
+	result, err := r.next.CreateAPIKey(ctx, key)
+	end(span, err)
+	return result, err
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, id int) error {
+	ctx, span := start(ctx, "APIKeyRepository.RevokeAPIKey")
+	err := r.next.RevokeAPIKey(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *apiKeyRepository) SetAPIKeyRole(ctx context.Context, id int, role string) error {
+	ctx, span := start(ctx, "APIKeyRepository.SetAPIKeyRole")
+	err := r.next.SetAPIKeyRole(ctx, id, role)
+	end(span, err)
+	return err
+}
+
+// userRepository instruments a models.UserRepository.
+type userRepository struct {
+	next models.UserRepository
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id int) (models.User, error) {
+	ctx, span := start(ctx, "UserRepository.GetUserByID")
+	result, err := r.next.GetUserByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	ctx, span := start(ctx, "UserRepository.GetUserByEmail")
+	result, err := r.next.GetUserByEmail(ctx, email)
+	end(span, err)
+	return result, err
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user models.User) (int, error) {
+	ctx, span := start(ctx, "UserRepository.CreateUser")
+	result, err := r.next.CreateUser(ctx, user)
+	end(span, err)
+	return result, err
+}
+
+func (r *userRepository) SetUserRole(ctx context.Context, id int, role string) error {
+	ctx, span := start(ctx, "UserRepository.SetUserRole")
+	err := r.next.SetUserRole(ctx, id, role)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,144 @@
+// Package tracing sets up OpenTelemetry tracing: the spans of the HTTP
+// requests, of the repository methods and of the SQL statements, exported
+// over OTLP or written as JSON to standard output or a file, and propagated
+// across services with the W3C Trace Context headers.
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans created by the API itself.
+const instrumentationName = "github.com/keevferreira/recipes-api"
+
+// The exporters Setup knows about.
+const (
+	// ExporterNone keeps tracing off, although incoming trace IDs still show
+	// up in the logs.
+	ExporterNone = "none"
+	// ExporterStdout writes the spans as JSON to standard output or a file.
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends the spans to an OpenTelemetry collector over HTTP.
+	ExporterOTLP = "otlp"
+)
+
+// Config configures tracing.
+type Config struct {
+	// Exporter is one of ExporterNone, ExporterStdout or ExporterOTLP.
+	Exporter string
+	// File is the file the stdout exporter appends to, or "" for standard output.
+	File string
+	// Endpoint is the URL of the OTLP collector, such as
+	// "http://localhost:4318". When empty, the OTEL_EXPORTER_OTLP_* variables
+	// or the exporter defaults apply.
+	Endpoint string
+	// SampleRatio is the fraction of the new traces that are recorded. Traces
+	// started by a caller follow the sampling decision of the caller.
+	SampleRatio float64
+
+	ServiceName string
+	Version     string
+}
+
+// Setup installs the global tracer provider and propagator described by
+// config. The returned function flushes the pending spans and releases the
+// exporter; it must be called before the process exits.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   io.Closer
+		err      error
+	)
+	switch config.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		var w io.Writer = os.Stdout
+		if config.File != "" {
+			file, err := os.OpenFile(config.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("opening the trace file: %w", err)
+			}
+			w, closer = file, file
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+		if config.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(config.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q, expected %s, %s or %s", config.Exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating the %s trace exporter: %w", config.Exporter, err)
+	}
+
+	service, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+		semconv.ServiceVersion(config.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("describing the service: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(service),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// Tracer returns the tracer of the API, backed by the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// OpenDB opens a database like sql.Open, with a span for every statement run
+// within a traced request. system is the semconv attribute naming the
+// database, such as semconv.DBSystemPostgreSQL.
+func OpenDB(driverName string, dataSourceName string, system attribute.KeyValue) (*sql.DB, error) {
+	return otelsql.Open(driverName, dataSourceName,
+		otelsql.WithAttributes(system),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+			OmitConnectorConnect: true,
+			OmitRows:             true,
+			// Statements run outside of a request, such as the migrations,
+			// would otherwise each start a trace of their own.
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanContextFromContext(ctx).IsValid()
+			},
+		}),
+	)
+}
@@ -0,0 +1,276 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dimension is the physical quantity measured by a unit.
+type Dimension string
+
+const (
+	Mass        Dimension = "mass"
+	Volume      Dimension = "volume"
+	Count       Dimension = "count"
+	Temperature Dimension = "temperature"
+)
+
+// System is a system of measurement.
+type System string
+
+const (
+	Metric System = "metric"
+	US     System = "us"
+)
+
+// Unit is a unit of measurement. Quantities are converted through the base
+// unit of each dimension: grams, milliliters, items and degrees Celsius.
+type Unit struct {
+	Symbol    string
+	Dimension Dimension
+	// System is empty for units shared by every system, like counts.
+	System System
+	// toBase is how many base units one of this unit is worth. It is not
+	// used for temperatures, which are not proportional.
+	toBase float64
+}
+
+// known lists every unit with the aliases it can be written as. Aliases are
+// stored lower case and without accents, see normalize.
+var known = []struct {
+	unit    Unit
+	aliases []string
+}{
+	{Unit{"mg", Mass, Metric, 0.001}, []string{"mg", "miligrama", "miligramas", "milligram", "milligrams"}},
+	{Unit{"g", Mass, Metric, 1}, []string{"g", "gr", "grama", "gramas", "gram", "grams"}},
+	{Unit{"kg", Mass, Metric, 1000}, []string{"kg", "quilo", "quilos", "quilograma", "quilogramas", "kilo", "kilos", "kilogram", "kilograms"}},
+	{Unit{"oz", Mass, US, 28.349523125}, []string{"oz", "ounce", "ounces", "onca", "oncas"}},
+	{Unit{"lb", Mass, US, 453.59237}, []string{"lb", "lbs", "pound", "pounds", "libra", "libras"}},
+
+	{Unit{"ml", Volume, Metric, 1}, []string{"ml", "mililitro", "mililitros", "milliliter", "milliliters", "millilitre", "millilitres"}},
+	{Unit{"l", Volume, Metric, 1000}, []string{"l", "lt", "litro", "litros", "liter", "liters", "litre", "litres"}},
+	{Unit{"colher de chá", Volume, Metric, 5}, []string{"colher de cha", "colheres de cha", "cc"}},
+	{Unit{"colher de sopa", Volume, Metric, 15}, []string{"colher de sopa", "colheres de sopa", "cs"}},
+	{Unit{"xícara", Volume, Metric, 240}, []string{"xicara", "xicaras", "xic"}},
+	{Unit{"tsp", Volume, US, 4.92892159375}, []string{"tsp", "teaspoon", "teaspoons"}},
+	{Unit{"tbsp", Volume, US, 14.78676478125}, []string{"tbsp", "tablespoon", "tablespoons"}},
+	{Unit{"fl oz", Volume, US, 29.5735295625}, []string{"fl oz", "floz", "fluid ounce", "fluid ounces"}},
+	{Unit{"cup", Volume, US, 236.5882365}, []string{"cup", "cups"}},
+	{Unit{"pint", Volume, US, 473.176473}, []string{"pint", "pints", "pt"}},
+	{Unit{"quart", Volume, US, 946.352946}, []string{"quart", "quarts", "qt"}},
+	{Unit{"gallon", Volume, US, 3785.411784}, []string{"gallon", "gallons", "gal", "galao", "galoes"}},
+
+	{Unit{"un", Count, "", 1}, []string{"un", "und", "unid", "unidade", "unidades", "unit", "units", "pc", "pcs", "piece", "pieces"}},
+	{Unit{"dúzia", Count, "", 12}, []string{"duzia", "duzias", "dozen", "dozens", "dz"}},
+
+	{Unit{"°C", Temperature, Metric, 0}, []string{"c", "°c", "ºc", "celsius", "graus celsius"}},
+	{Unit{"°F", Temperature, US, 0}, []string{"f", "°f", "ºf", "fahrenheit", "graus fahrenheit"}},
+	{Unit{"K", Temperature, "", 0}, []string{"k", "kelvin"}},
+}
+
+// byAlias indexes every unit by its normalized aliases and symbol.
+var byAlias = func() map[string]Unit {
+	index := make(map[string]Unit)
+	for _, entry := range known {
+		index[normalize(entry.unit.Symbol)] = entry.unit
+		for _, alias := range entry.aliases {
+			index[normalize(alias)] = entry.unit
+		}
+	}
+	return index
+}()
+
+// accents removes the accents used in Portuguese unit names.
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u",
+	"ç", "c",
+)
+
+// normalize lower cases a unit name, strips accents, a trailing period and
+// repeated spaces, so "Xícaras", "xicaras" and " xícaras. " are equal.
+func normalize(name string) string {
+	name = accents.Replace(strings.ToLower(strings.TrimSpace(name)))
+	name = strings.TrimSuffix(name, ".")
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Lookup finds a unit by its symbol or by any of its aliases.
+func Lookup(name string) (Unit, bool) {
+	unit, ok := byAlias[normalize(name)]
+	return unit, ok
+}
+
+// ParseSystem parses "metric" or "us".
+func ParseSystem(name string) (System, error) {
+	switch System(strings.ToLower(name)) {
+	case Metric:
+		return Metric, nil
+	case US:
+		return US, nil
+	}
+	return "", fmt.Errorf("unknown unit system %q, expected %q or %q", name, Metric, US)
+}
+
+// Convert converts quantity between two units of the same dimension.
+func Convert(quantity float64, from string, to string) (float64, error) {
+	return ConvertWithDensity(quantity, from, to, 0)
+}
+
+// ConvertWithDensity converts quantity between two units. Conversions between
+// mass and volume use density, in grams per milliliter, and fail if it is not
+// positive.
+func ConvertWithDensity(quantity float64, from string, to string, density float64) (float64, error) {
+	fromUnit, ok := Lookup(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	toUnit, ok := Lookup(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+
+	if fromUnit.Dimension == Temperature || toUnit.Dimension == Temperature {
+		if fromUnit.Dimension != toUnit.Dimension {
+			return 0, fmt.Errorf("cannot convert %s to %s", fromUnit.Symbol, toUnit.Symbol)
+		}
+		return fromCelsius(toCelsius(quantity, fromUnit), toUnit), nil
+	}
+
+	base := quantity * fromUnit.toBase
+	switch {
+	case fromUnit.Dimension == toUnit.Dimension:
+	case fromUnit.Dimension == Volume && toUnit.Dimension == Mass && density > 0:
+		base *= density
+	case fromUnit.Dimension == Mass && toUnit.Dimension == Volume && density > 0:
+		base /= density
+	case density <= 0 && isMassVolume(fromUnit, toUnit):
+		return 0, fmt.Errorf("converting %s to %s needs the ingredient density", fromUnit.Symbol, toUnit.Symbol)
+	default:
+		return 0, fmt.Errorf("cannot convert %s to %s", fromUnit.Symbol, toUnit.Symbol)
+	}
+
+	return base / toUnit.toBase, nil
+}
+
+// ToSystem expresses quantity in the most readable unit of the given system
+// and the same dimension. Units without a system, like counts, and unknown
+// units are returned unchanged.
+func ToSystem(quantity float64, unit string, system System) (float64, string) {
+	from, ok := Lookup(unit)
+	if !ok || from.System == "" || from.System == system {
+		return quantity, unit
+	}
+
+	to := bestUnit(quantity, from, system)
+	converted, err := Convert(quantity, from.Symbol, to)
+	if err != nil {
+		return quantity, unit
+	}
+
+	return converted, to
+}
+
+// bestUnit picks the unit of system that reads best for quantity of from.
+func bestUnit(quantity float64, from Unit, system System) string {
+	if from.Dimension == Temperature {
+		if system == US {
+			return "°F"
+		}
+		return "°C"
+	}
+
+	base := quantity * from.toBase
+	switch {
+	case from.Dimension == Mass && system == Metric:
+		if base >= 1000 {
+			return "kg"
+		}
+		return "g"
+	case from.Dimension == Mass && system == US:
+		if base >= 453.59237 {
+			return "lb"
+		}
+		return "oz"
+	case from.Dimension == Volume && system == Metric:
+		if base >= 1000 {
+			return "l"
+		}
+		return "ml"
+	default:
+		// US volume, in the measures used in recipes
+		switch {
+		case base < 14.78676478125:
+			return "tsp"
+		case base < 236.5882365/4:
+			return "tbsp"
+		default:
+			return "cup"
+		}
+	}
+}
+
+func isMassVolume(a Unit, b Unit) bool {
+	return (a.Dimension == Mass && b.Dimension == Volume) || (a.Dimension == Volume && b.Dimension == Mass)
+}
+
+func toCelsius(value float64, unit Unit) float64 {
+	switch unit.Symbol {
+	case "°F":
+		return (value - 32) * 5 / 9
+	case "K":
+		return value - 273.15
+	}
+	return value
+}
+
+func fromCelsius(value float64, unit Unit) float64 {
+	switch unit.Symbol {
+	case "°F":
+		return value*9/5 + 32
+	case "K":
+		return value + 273.15
+	}
+	return value
+}
+
+// Base returns quantity in the base unit of its dimension (grams, milliliters
+// or items) together with that unit's symbol. Temperatures and unknown units
+// are returned unchanged.
+func Base(quantity float64, unit string) (float64, string) {
+	from, ok := Lookup(unit)
+	if !ok {
+		return quantity, unit
+	}
+
+	switch from.Dimension {
+	case Mass:
+		return quantity * from.toBase, "g"
+	case Volume:
+		return quantity * from.toBase, "ml"
+	case Count:
+		return quantity * from.toBase, "un"
+	}
+	return quantity, unit
+}
+
+// Readable expresses quantity in the unit of the same dimension and system
+// that reads best, e.g. 1500 g as 1.5 kg. Units without a system and unknown
+// units are returned unchanged.
+func Readable(quantity float64, unit string) (float64, string) {
+	from, ok := Lookup(unit)
+	if !ok || from.System == "" || from.Dimension == Temperature {
+		return quantity, unit
+	}
+
+	to := bestUnit(quantity, from, from.System)
+	converted, err := Convert(quantity, from.Symbol, to)
+	if err != nil {
+		return quantity, unit
+	}
+
+	return converted, to
+}
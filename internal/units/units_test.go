@@ -0,0 +1,157 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		symbol string
+		ok     bool
+	}{
+		{"g", "g", true},
+		{"Gramas", "g", true},
+		{" xícaras. ", "xícara", true},
+		{"Colheres de Sopa", "colher de sopa", true},
+		{"fluid ounces", "fl oz", true},
+		{"ºC", "°C", true},
+		{"dz", "dúzia", true},
+		{"punhado", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		unit, ok := Lookup(test.name)
+		if ok != test.ok || unit.Symbol != test.symbol {
+			t.Errorf("Lookup(%q) = %q, %t, want %q, %t", test.name, unit.Symbol, ok, test.symbol, test.ok)
+		}
+	}
+}
+
+func TestConvertWithDensity(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		from     string
+		to       string
+		density  float64
+		want     float64
+		fails    bool
+	}{
+		{1, "kg", "g", 0, 1000, false},
+		{500, "g", "kg", 0, 0.5, false},
+		{1, "lb", "g", 0, 453.59237, false},
+		{2, "xícara", "ml", 0, 480, false},
+		{1, "cup", "tbsp", 0, 16, false},
+		{3, "tsp", "tbsp", 0, 1, false},
+		{1, "gallon", "quart", 0, 4, false},
+		{2, "dúzia", "un", 0, 24, false},
+		{100, "°C", "°F", 0, 212, false},
+		{212, "°F", "K", 0, 373.15, false},
+		{1, "xícara", "g", 0.5, 120, false},
+		{120, "g", "xícara", 0.5, 1, false},
+		{1, "xícara", "g", 0, 0, true},
+		{1, "kg", "un", 0, 0, true},
+		{1, "°C", "g", 0, 0, true},
+		{1, "punhado", "g", 0, 0, true},
+		{1, "g", "punhado", 0, 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := ConvertWithDensity(test.quantity, test.from, test.to, test.density)
+		if (err != nil) != test.fails {
+			t.Errorf("ConvertWithDensity(%v, %q, %q, %v) error = %v, want failure %t", test.quantity, test.from, test.to, test.density, err, test.fails)
+			continue
+		}
+		if math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("ConvertWithDensity(%v, %q, %q, %v) = %v, want %v", test.quantity, test.from, test.to, test.density, got, test.want)
+		}
+	}
+}
+
+func TestBase(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		unit     string
+		want     float64
+		wantUnit string
+	}{
+		{1.5, "kg", 1500, "g"},
+		{2, "l", 2000, "ml"},
+		{1, "colher de sopa", 15, "ml"},
+		{1, "dúzia", 12, "un"},
+		{180, "°C", 180, "°C"},
+		{2, "maço", 2, "maço"},
+	}
+
+	for _, test := range tests {
+		got, unit := Base(test.quantity, test.unit)
+		if math.Abs(got-test.want) > 1e-9 || unit != test.wantUnit {
+			t.Errorf("Base(%v, %q) = %v %s, want %v %s", test.quantity, test.unit, got, unit, test.want, test.wantUnit)
+		}
+	}
+}
+
+func TestToSystem(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		unit     string
+		system   System
+		want     float64
+		wantUnit string
+	}{
+		{1, "lb", Metric, 453.59237, "g"},
+		{3, "lb", Metric, 1.36077711, "kg"},
+		{100, "g", US, 3.527396195, "oz"},
+		{1, "kg", US, 2.204622622, "lb"},
+		{1000, "ml", US, 4.226752838, "cup"},
+		{10, "ml", US, 2.028841362, "tsp"},
+		{2, "cup", Metric, 473.176473, "ml"},
+		{350, "°F", Metric, 176.666666667, "°C"},
+		{200, "g", Metric, 200, "g"},
+		{3, "un", US, 3, "un"},
+		{2, "maço", US, 2, "maço"},
+	}
+
+	for _, test := range tests {
+		got, unit := ToSystem(test.quantity, test.unit, test.system)
+		if math.Abs(got-test.want) > 1e-6 || unit != test.wantUnit {
+			t.Errorf("ToSystem(%v, %q, %s) = %v %s, want %v %s", test.quantity, test.unit, test.system, got, unit, test.want, test.wantUnit)
+		}
+	}
+}
+
+func TestReadable(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		unit     string
+		want     float64
+		wantUnit string
+	}{
+		{1500, "g", 1.5, "kg"},
+		{0.25, "kg", 250, "g"},
+		{2500, "ml", 2.5, "l"},
+		{24, "oz", 1.5, "lb"},
+		{3, "un", 3, "un"},
+		{180, "°C", 180, "°C"},
+	}
+
+	for _, test := range tests {
+		got, unit := Readable(test.quantity, test.unit)
+		if math.Abs(got-test.want) > 1e-9 || unit != test.wantUnit {
+			t.Errorf("Readable(%v, %q) = %v %s, want %v %s", test.quantity, test.unit, got, unit, test.want, test.wantUnit)
+		}
+	}
+}
+
+func TestParseSystem(t *testing.T) {
+	for _, name := range []string{"metric", "US"} {
+		if _, err := ParseSystem(name); err != nil {
+			t.Errorf("ParseSystem(%q): %v", name, err)
+		}
+	}
+	if _, err := ParseSystem("imperial"); err == nil {
+		t.Errorf("ParseSystem(%q) succeeded, want an error", "imperial")
+	}
+}
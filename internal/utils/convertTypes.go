@@ -2,15 +2,14 @@ package utils
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 )
 
-func StringToInt(str string) int {
-	int, err := strconv.Atoi(str)
+// StringToInt converte uma string em inteiro, retornando um erro se ela não for numérica.
+func StringToInt(str string) (int, error) {
+	value, err := strconv.Atoi(str)
 	if err != nil {
-		fmt.Println("Erro ao converter string para inteiro:", err)
-		os.Exit(1)
+		return 0, fmt.Errorf("erro ao converter %q para inteiro: %w", str, err)
 	}
-	return int
+	return value, nil
 }
@@ -1,13 +1,12 @@
 package utils
 
-import (
-	"fmt"
-	"os"
-)
+import "fmt"
 
-func TreatNilObjectError(obj error, errorMessage string) {
-	if obj != nil {
-		fmt.Print(errorMessage)
-		os.Exit(1)
+// WrapError acrescenta a mensagem informada a um erro, preservando-o para errors.Is e errors.As.
+// Retorna nil se o erro for nil.
+func WrapError(err error, errorMessage string) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", errorMessage, err)
 	}
+	return nil
 }
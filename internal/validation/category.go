@@ -0,0 +1,8 @@
+package validation
+
+import "github.com/keevferreira/recipes-api/internal/models"
+
+// Category holds the rules of a category.
+var Category = Rules[models.Category]{
+	Field("name", func(c models.Category) string { return c.Name }, Required, MaxLength(255)),
+}
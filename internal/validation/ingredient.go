@@ -0,0 +1,10 @@
+package validation
+
+import "github.com/keevferreira/recipes-api/internal/models"
+
+// Ingredient holds the rules of an ingredient.
+var Ingredient = Rules[models.Ingredient]{
+	Field("name", func(i models.Ingredient) string { return i.Name }, Required, MaxLength(255)),
+	Field("density", func(i models.Ingredient) *float64 { return i.Density }, Optional(Greater(0.0))),
+	Field("aisle", func(i models.Ingredient) string { return i.Aisle }, MaxLength(100)),
+}
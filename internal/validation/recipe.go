@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+	"github.com/keevferreira/recipes-api/internal/units"
+)
+
+// Recipe returns the rules of a recipe. The ingredients and categories it
+// links are checked against their repositories within ctx, so the rules are
+// built for each request. They are looked up with one read per repository,
+// whatever the number of IDs.
+func Recipe(ctx context.Context, ingredients models.IngredientRepository, categories models.CategoryRepository) Rules[models.Recipe] {
+	return Rules[models.Recipe]{
+		Field("title", func(r models.Recipe) string { return r.Title }, Required, MaxLength(255)),
+		Field("prep_time", func(r models.Recipe) int { return r.PrepTime }, Min(0)),
+		Field("servings", func(r models.Recipe) int { return r.Servings }, Min(0)),
+		Field("difficulty", func(r models.Recipe) string { return r.Difficulty }, OneOf(models.Difficulties...)),
+		With(func(r models.Recipe) Check[models.Recipe] {
+			ingredientExists, err := ExistAll(ctx, ingredients.GetIngredientsByIDs, func(i models.Ingredient) int { return i.ID }, "ingredient", referencedIngredientIDs(r))
+			if err != nil {
+				return fail[models.Recipe](err)
+			}
+			categoryExists, err := ExistAll(ctx, categories.GetCategoriesByIDs, func(c models.Category) int { return c.ID }, "category", recipeCategoryIDs(r))
+			if err != nil {
+				return fail[models.Recipe](err)
+			}
+
+			return Rules[models.Recipe]{
+				Field("ingredients", recipeIngredientIDs, Distinct[int]),
+				Each("ingredients", func(r models.Recipe) []models.RecipeIngredient { return r.Ingredients }, recipeIngredient(ingredientExists)),
+				Field("category", recipeCategoryIDs, Distinct[int]),
+				Each("category", func(r models.Recipe) []models.Category { return r.Categories }, Rules[models.Category]{
+					Field("id", func(c models.Category) int { return c.ID }, NonZero[int], categoryExists),
+				}),
+				Each("steps", func(r models.Recipe) []models.Step { return r.Steps }, step(ingredientExists, recipeIngredientIDs(r))),
+			}.check
+		}),
+	}
+}
+
+// recipeIngredient returns the rules of an ingredient line of a recipe.
+// exists accepts the ingredients of the catalog.
+func recipeIngredient(exists Rule[int]) Rules[models.RecipeIngredient] {
+	return Rules[models.RecipeIngredient]{
+		Field("ingredient_id", func(i models.RecipeIngredient) int { return i.IngredientID }, NonZero[int], exists),
+		Field("quantity", func(i models.RecipeIngredient) float64 { return i.Quantity }, Min(0.0)),
+		Field("unit", func(i models.RecipeIngredient) string { return i.Unit }, MaxLength(50)),
+	}
+}
+
+// step returns the rules of a preparation step of a recipe whose ingredient
+// lines use lines. The ingredients of a step must exist, as accepted by
+// exists, and be among them.
+func step(exists Rule[int], lines []int) Rules[models.Step] {
+	return Rules[models.Step]{
+		Field("text", func(s models.Step) string { return s.Text }, Required),
+		Field("duration", func(s models.Step) *int { return s.Duration }, Optional(Min(0))),
+		Field("temperature_unit", func(s models.Step) models.Step { return s }, temperatureUnit),
+		Each("ingredient_ids", func(s models.Step) []int { return s.IngredientIDs }, Rules[int]{
+			Value(NonZero[int], exists, listedIn(lines)),
+		}),
+	}
+}
+
+// listedIn rejects the ingredients missing from the ingredient lines of the recipe.
+func listedIn(lines []int) Rule[int] {
+	return func(id int) error {
+		for _, line := range lines {
+			if id == line {
+				return nil
+			}
+		}
+
+		return violation(models.FieldInvalid, "ingredient %d is not an ingredient of the recipe", id)
+	}
+}
+
+// temperatureUnit requires the unit of the temperature of a step when it has
+// one, and rejects a unit without a temperature.
+func temperatureUnit(s models.Step) error {
+	if s.Temperature == nil {
+		if s.TemperatureUnit != "" {
+			return violation(models.FieldInvalid, "must be empty when temperature is not set")
+		}
+		return nil
+	}
+
+	unit, ok := units.Lookup(s.TemperatureUnit)
+	if !ok || unit.Dimension != units.Temperature {
+		return violation(models.FieldInvalid, "must be a temperature unit when temperature is set")
+	}
+
+	return nil
+}
+
+func recipeIngredientIDs(r models.Recipe) []int {
+	ids := make([]int, 0, len(r.Ingredients))
+	for _, ingredient := range r.Ingredients {
+		ids = append(ids, ingredient.IngredientID)
+	}
+
+	return ids
+}
+
+// referencedIngredientIDs returns the IDs of the ingredients of the lines and
+// the steps of a recipe.
+func referencedIngredientIDs(r models.Recipe) []int {
+	ids := recipeIngredientIDs(r)
+	for _, step := range r.Steps {
+		ids = append(ids, step.IngredientIDs...)
+	}
+
+	return ids
+}
+
+func recipeCategoryIDs(r models.Recipe) []int {
+	ids := make([]int, 0, len(r.Categories))
+	for _, category := range r.Categories {
+		ids = append(ids, category.ID)
+	}
+
+	return ids
+}
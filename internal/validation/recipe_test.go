@@ -0,0 +1,164 @@
+package validation_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/keevferreira/recipes-api/internal/database/memory"
+	"github.com/keevferreira/recipes-api/internal/models"
+	"github.com/keevferreira/recipes-api/internal/validation"
+)
+
+func TestRecipe(t *testing.T) {
+	ctx := context.Background()
+	repositories := memory.NewRepositories(memory.NewStore())
+	flour, _ := repositories.Ingredients.CreateIngredient(ctx, models.Ingredient{Name: "Farinha"})
+	eggs, _ := repositories.Ingredients.CreateIngredient(ctx, models.Ingredient{Name: "Ovos"})
+	category, _ := repositories.Categories.CreateCategory(ctx, models.Category{Name: "Massas"})
+
+	valid := func() models.Recipe {
+		temperature := 180.0
+		return models.Recipe{
+			Title:      "Bolo",
+			Difficulty: models.DifficultyEasy,
+			Ingredients: []models.RecipeIngredient{
+				{IngredientID: flour, Quantity: 200, Unit: "g"},
+				{IngredientID: eggs, Quantity: 3, Unit: "un"},
+			},
+			Categories: []models.Category{{ID: category}},
+			Steps: []models.Step{
+				{Text: "Misture.", IngredientIDs: []int{flour, eggs}},
+				{Text: "Asse.", Temperature: &temperature, TemperatureUnit: "°C"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(r *models.Recipe)
+		fields []string
+	}{
+		{"valid", func(r *models.Recipe) {}, nil},
+		{"title", func(r *models.Recipe) { r.Title = " " }, []string{"title:required"}},
+		{"difficulty", func(r *models.Recipe) { r.Difficulty = "trivial" }, []string{"difficulty:invalid"}},
+		{"negative servings", func(r *models.Recipe) { r.Servings = -1 }, []string{"servings:invalid"}},
+		{"repeated ingredient", func(r *models.Recipe) { r.Ingredients[1].IngredientID = flour }, []string{
+			"ingredients:invalid", "steps[0].ingredient_ids[1]:invalid",
+		}},
+		{"missing ingredient", func(r *models.Recipe) { r.Ingredients[1].IngredientID = 99 }, []string{
+			"ingredients[1].ingredient_id:not_found", "steps[0].ingredient_ids[1]:invalid",
+		}},
+		{"negative quantity", func(r *models.Recipe) { r.Ingredients[0].Quantity = -1 }, []string{"ingredients[0].quantity:invalid"}},
+		{"missing category", func(r *models.Recipe) { r.Categories[0].ID = 99 }, []string{"category[0].id:not_found"}},
+		{"empty step", func(r *models.Recipe) { r.Steps[1].Text = "" }, []string{"steps[1].text:required"}},
+		{"temperature without unit", func(r *models.Recipe) { r.Steps[1].TemperatureUnit = "" }, []string{"steps[1].temperature_unit:invalid"}},
+		{"temperature in grams", func(r *models.Recipe) { r.Steps[1].TemperatureUnit = "g" }, []string{"steps[1].temperature_unit:invalid"}},
+		{"unit without temperature", func(r *models.Recipe) { r.Steps[0].TemperatureUnit = "graus celsius" }, []string{"steps[0].temperature_unit:invalid"}},
+		{"step ingredient not in the recipe", func(r *models.Recipe) { r.Ingredients = r.Ingredients[:1] }, []string{"steps[0].ingredient_ids[1]:invalid"}},
+		{"step ingredient missing", func(r *models.Recipe) { r.Steps[0].IngredientIDs = []int{flour, 99} }, []string{"steps[0].ingredient_ids[1]:not_found"}},
+		{"step ingredient zero", func(r *models.Recipe) { r.Steps[1].IngredientIDs = []int{0} }, []string{"steps[1].ingredient_ids[0]:required"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recipe := valid()
+			test.change(&recipe)
+
+			err := validation.Recipe(ctx, repositories.Ingredients, repositories.Categories).Validate(recipe)
+
+			var fields []string
+			var invalid *models.ValidationError
+			switch {
+			case err == nil:
+			case errors.As(err, &invalid):
+				for _, field := range invalid.Fields {
+					fields = append(fields, field.Field+":"+field.Code)
+				}
+			default:
+				t.Fatalf("error = %v, want a validation error", err)
+			}
+
+			if !slices.Equal(fields, test.fields) {
+				t.Errorf("fields = %v, want %v", fields, test.fields)
+			}
+		})
+	}
+}
+
+// countingIngredients and countingCategories count the reads of the
+// repositories they wrap.
+type countingIngredients struct {
+	models.IngredientRepository
+	reads int
+}
+
+func (c *countingIngredients) GetIngredientByID(ctx context.Context, id int) (models.Ingredient, error) {
+	c.reads++
+	return c.IngredientRepository.GetIngredientByID(ctx, id)
+}
+
+func (c *countingIngredients) GetIngredientsByIDs(ctx context.Context, ids []int) ([]models.Ingredient, error) {
+	c.reads++
+	return c.IngredientRepository.GetIngredientsByIDs(ctx, ids)
+}
+
+type countingCategories struct {
+	models.CategoryRepository
+	reads int
+}
+
+func (c *countingCategories) GetCategoryByID(ctx context.Context, id int) (models.Category, error) {
+	c.reads++
+	return c.CategoryRepository.GetCategoryByID(ctx, id)
+}
+
+func (c *countingCategories) GetCategoriesByIDs(ctx context.Context, ids []int) ([]models.Category, error) {
+	c.reads++
+	return c.CategoryRepository.GetCategoriesByIDs(ctx, ids)
+}
+
+func TestRecipeReads(t *testing.T) {
+	ctx := context.Background()
+	repositories := memory.NewRepositories(memory.NewStore())
+	ingredients := &countingIngredients{IngredientRepository: repositories.Ingredients}
+	categories := &countingCategories{CategoryRepository: repositories.Categories}
+
+	recipe := models.Recipe{Title: "Salada", Difficulty: models.DifficultyEasy}
+	for _, name := range []string{"Alface", "Tomate", "Cebola", "Azeite"} {
+		id, _ := repositories.Ingredients.CreateIngredient(ctx, models.Ingredient{Name: name})
+		recipe.Ingredients = append(recipe.Ingredients, models.RecipeIngredient{IngredientID: id, Quantity: 1, Unit: "un"})
+		recipe.Steps = append(recipe.Steps, models.Step{Text: "Corte.", IngredientIDs: []int{id}})
+	}
+	for _, name := range []string{"Saladas", "Veganas"} {
+		id, _ := repositories.Categories.CreateCategory(ctx, models.Category{Name: name})
+		recipe.Categories = append(recipe.Categories, models.Category{ID: id})
+	}
+
+	if err := validation.Recipe(ctx, ingredients, categories).Validate(recipe); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if ingredients.reads != 1 || categories.reads != 1 {
+		t.Errorf("reads = %d ingredients, %d categories, want one of each", ingredients.reads, categories.reads)
+	}
+}
+
+func TestRegistration(t *testing.T) {
+	tests := []struct {
+		registration models.Registration
+		valid        bool
+	}{
+		{models.Registration{Email: "cook@example.com", Name: "Ana", Password: "12345678"}, true},
+		{models.Registration{Email: "cook", Name: "Ana", Password: "12345678"}, false},
+		{models.Registration{Email: "cook@example.com", Name: "", Password: "12345678"}, false},
+		{models.Registration{Email: "cook@example.com", Name: "Ana", Password: "1234567"}, false},
+	}
+
+	for _, test := range tests {
+		err := validation.Registration.Validate(test.registration)
+		if (err == nil) != test.valid {
+			t.Errorf("Validate(%+v) = %v, want valid %t", test.registration, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package validation
+
+import "github.com/keevferreira/recipes-api/internal/models"
+
+// Registration holds the rules of the input creating a user. Passwords are
+// capped at 72 bytes, the most bcrypt hashes.
+var Registration = Rules[models.Registration]{
+	Field("email", func(r models.Registration) string { return r.Email }, Required, MaxLength(255), Email),
+	Field("name", func(r models.Registration) string { return r.Name }, Required, MaxLength(255)),
+	Field("password", func(r models.Registration) string { return r.Password }, MinLength(8), MaxBytes(72)),
+}
+
+// RoleAssignment holds the rules of the input changing the role of a user.
+var RoleAssignment = Rules[models.RoleAssignment]{
+	Field("role", func(a models.RoleAssignment) string { return a.Role }, Required, OneOf(models.Roles...)),
+}
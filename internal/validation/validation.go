@@ -0,0 +1,333 @@
+// Package validation checks input models against declarative rules. The rules
+// of a model list its fields, each with the rules its value must follow, and
+// a failed validation reports every broken rule as a models.FieldError.
+package validation
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+// Violation is a broken rule. Rules return it to report invalid values; any
+// other error they return aborts the validation.
+type Violation struct {
+	// Code is one of the field error codes of the models package.
+	Code    string
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return v.Message
+}
+
+// violation creates a Violation with a formatted message.
+func violation(code string, format string, args ...any) error {
+	return &Violation{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Rule checks a value. It returns a *Violation when the value is invalid.
+type Rule[T any] func(value T) error
+
+// Check applies rules to a part of a model of type M, appending the field
+// errors found under path.
+type Check[M any] func(model M, path string, fields *[]models.FieldError) error
+
+// Rules are the checks of a model.
+type Rules[M any] []Check[M]
+
+// Validate applies the rules to model. It returns a *models.ValidationError
+// listing every broken rule, or the error of a rule that could not run.
+func (rs Rules[M]) Validate(model M) error {
+	var fields []models.FieldError
+	if err := rs.check(model, "", &fields); err != nil {
+		return err
+	}
+
+	if len(fields) > 0 {
+		return models.NewValidationError(fields...)
+	}
+
+	return nil
+}
+
+func (rs Rules[M]) check(model M, path string, fields *[]models.FieldError) error {
+	for _, check := range rs {
+		if err := check(model, path, fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Field checks the value returned by get, named name in the JSON input,
+// against rules. Only the first broken rule of a field is reported.
+func Field[M any, T any](name string, get func(M) T, rules ...Rule[T]) Check[M] {
+	return func(model M, path string, fields *[]models.FieldError) error {
+		value := get(model)
+		for _, rule := range rules {
+			err := rule(value)
+			if err == nil {
+				continue
+			}
+
+			var broken *Violation
+			if !errors.As(err, &broken) {
+				return err
+			}
+
+			*fields = append(*fields, models.FieldError{Field: join(path, name), Code: broken.Code, Message: broken.Message})
+			return nil
+		}
+
+		return nil
+	}
+}
+
+// Value checks a model that is itself a value, such as an element of a slice
+// of IDs, against rules. Its errors are reported under the path of the model.
+func Value[T any](rules ...Rule[T]) Check[T] {
+	return func(value T, path string, fields *[]models.FieldError) error {
+		for _, rule := range rules {
+			err := rule(value)
+			if err == nil {
+				continue
+			}
+
+			var broken *Violation
+			if !errors.As(err, &broken) {
+				return err
+			}
+
+			*fields = append(*fields, models.FieldError{Field: path, Code: broken.Code, Message: broken.Message})
+			return nil
+		}
+
+		return nil
+	}
+}
+
+// With builds the check of a model from the model itself, for rules that
+// depend on other fields of the model.
+func With[M any](build func(M) Check[M]) Check[M] {
+	return func(model M, path string, fields *[]models.FieldError) error {
+		return build(model)(model, path, fields)
+	}
+}
+
+// Each checks every element of the slice returned by get against rules,
+// reporting the errors of the element at index i under name[i].
+func Each[M any, E any](name string, get func(M) []E, rules Rules[E]) Check[M] {
+	return func(model M, path string, fields *[]models.FieldError) error {
+		for i, element := range get(model) {
+			if err := rules.check(element, join(path, name)+"["+strconv.Itoa(i)+"]", fields); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// join appends the field name to the path of its parent.
+func join(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// Required rejects blank strings.
+func Required(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return violation(models.FieldRequired, "must not be empty")
+	}
+
+	return nil
+}
+
+// NonZero rejects the zero value, such as a missing ID.
+func NonZero[T comparable](value T) error {
+	var zero T
+	if value == zero {
+		return violation(models.FieldRequired, "is required")
+	}
+
+	return nil
+}
+
+// MaxLength rejects strings longer than n characters.
+func MaxLength(n int) Rule[string] {
+	return func(value string) error {
+		if utf8.RuneCountInString(value) > n {
+			return violation(models.FieldInvalid, "must be at most %d characters long", n)
+		}
+
+		return nil
+	}
+}
+
+// MinLength rejects strings shorter than n characters.
+func MinLength(n int) Rule[string] {
+	return func(value string) error {
+		if utf8.RuneCountInString(value) < n {
+			return violation(models.FieldInvalid, "must be at least %d characters long", n)
+		}
+
+		return nil
+	}
+}
+
+// MaxBytes rejects strings longer than n bytes once encoded in UTF-8.
+func MaxBytes(n int) Rule[string] {
+	return func(value string) error {
+		if len(value) > n {
+			return violation(models.FieldInvalid, "must be at most %d bytes long", n)
+		}
+
+		return nil
+	}
+}
+
+// Email rejects strings that are not a bare e-mail address, such as
+// "cook@example.com". Blank strings are left to Required.
+func Email(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value || address.Name != "" {
+		return violation(models.FieldInvalid, "must be an e-mail address")
+	}
+
+	return nil
+}
+
+// Min rejects values lower than n.
+func Min[T cmp.Ordered](n T) Rule[T] {
+	return func(value T) error {
+		if value < n {
+			return violation(models.FieldInvalid, "must be at least %v", n)
+		}
+
+		return nil
+	}
+}
+
+// Greater rejects values lower than or equal to n.
+func Greater[T cmp.Ordered](n T) Rule[T] {
+	return func(value T) error {
+		if value <= n {
+			return violation(models.FieldInvalid, "must be greater than %v", n)
+		}
+
+		return nil
+	}
+}
+
+// OneOf rejects values other than values.
+func OneOf[T comparable](values ...T) Rule[T] {
+	return func(value T) error {
+		for _, allowed := range values {
+			if value == allowed {
+				return nil
+			}
+		}
+
+		names := make([]string, 0, len(values))
+		for _, allowed := range values {
+			names = append(names, fmt.Sprint(allowed))
+		}
+
+		return violation(models.FieldInvalid, "must be one of %s", strings.Join(names, ", "))
+	}
+}
+
+// Distinct rejects slices holding a value more than once.
+func Distinct[T comparable](values []T) error {
+	seen := make(map[T]bool, len(values))
+	for _, value := range values {
+		if seen[value] {
+			return violation(models.FieldInvalid, "lists %v more than once", value)
+		}
+		seen[value] = true
+	}
+
+	return nil
+}
+
+// Optional applies rules to the value of a pointer, accepting nil.
+func Optional[T any](rules ...Rule[T]) Rule[*T] {
+	return func(value *T) error {
+		if value == nil {
+			return nil
+		}
+
+		for _, rule := range rules {
+			if err := rule(*value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// Exists checks that the record with the ID exists, using the lookup of its
+// repository within ctx. Zero IDs are left to NonZero.
+func Exists[T any](ctx context.Context, lookup func(ctx context.Context, id int) (T, error)) Rule[int] {
+	return func(id int) error {
+		if id == 0 {
+			return nil
+		}
+
+		_, err := lookup(ctx, id)
+		if errors.Is(err, models.ErrNotFound) {
+			return violation(models.FieldNotFound, "%s", err.Error())
+		}
+
+		return err
+	}
+}
+
+// ExistAll looks the IDs up at once with the batched lookup of their
+// repository within ctx, such as GetIngredientsByIDs, and returns a rule
+// accepting the IDs it found. kind names the records in the messages. Zero
+// IDs are left to NonZero.
+func ExistAll[T any](ctx context.Context, lookup func(ctx context.Context, ids []int) ([]T, error), id func(T) int, kind string, ids []int) (Rule[int], error) {
+	records, err := lookup(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[int]bool, len(records))
+	for _, record := range records {
+		found[id(record)] = true
+	}
+
+	return func(id int) error {
+		if id == 0 || found[id] {
+			return nil
+		}
+
+		return violation(models.FieldNotFound, "%s", models.NewNotFoundError(kind, id).Error())
+	}, nil
+}
+
+// fail is a check that aborts the validation with err, for the checks that
+// could not be built.
+func fail[M any](err error) Check[M] {
+	return func(model M, path string, fields *[]models.FieldError) error {
+		return err
+	}
+}
@@ -0,0 +1,184 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/keevferreira/recipes-api/internal/models"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  func() error
+		code  string
+		valid bool
+	}{
+		{"Required", func() error { return Required("bolo") }, "", true},
+		{"Required blank", func() error { return Required("  ") }, models.FieldRequired, false},
+		{"NonZero", func() error { return NonZero(3) }, "", true},
+		{"NonZero zero", func() error { return NonZero(0) }, models.FieldRequired, false},
+		{"MaxLength counts characters", func() error { return MaxLength(4)("pães") }, "", true},
+		{"MaxLength too long", func() error { return MaxLength(3)("pães") }, models.FieldInvalid, false},
+		{"MinLength too short", func() error { return MinLength(8)("senha") }, models.FieldInvalid, false},
+		{"MaxBytes counts bytes", func() error { return MaxBytes(4)("pães") }, models.FieldInvalid, false},
+		{"Email", func() error { return Email("cook@example.com") }, "", true},
+		{"Email empty", func() error { return Email("") }, "", true},
+		{"Email with a name", func() error { return Email("Cook <cook@example.com>") }, models.FieldInvalid, false},
+		{"Email invalid", func() error { return Email("cook") }, models.FieldInvalid, false},
+		{"Min", func() error { return Min(0)(0) }, "", true},
+		{"Min below", func() error { return Min(0)(-1) }, models.FieldInvalid, false},
+		{"Greater equal", func() error { return Greater(0.0)(0) }, models.FieldInvalid, false},
+		{"OneOf", func() error { return OneOf("a", "b")("b") }, "", true},
+		{"OneOf other", func() error { return OneOf("a", "b")("c") }, models.FieldInvalid, false},
+		{"Distinct", func() error { return Distinct([]int{1, 2, 3}) }, "", true},
+		{"Distinct repeated", func() error { return Distinct([]int{1, 2, 1}) }, models.FieldInvalid, false},
+		{"Optional nil", func() error { return Optional(Min(0))(nil) }, "", true},
+		{"Optional value", func() error { n := -1; return Optional(Min(0))(&n) }, models.FieldInvalid, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.rule()
+			if test.valid {
+				if err != nil {
+					t.Errorf("error = %v, want none", err)
+				}
+				return
+			}
+
+			var broken *Violation
+			if !errors.As(err, &broken) || broken.Code != test.code {
+				t.Errorf("error = %v, want a violation with code %q", err, test.code)
+			}
+		})
+	}
+}
+
+func TestExists(t *testing.T) {
+	failure := errors.New("connection refused")
+	lookup := func(ctx context.Context, id int) (models.Category, error) {
+		switch id {
+		case 1:
+			return models.Category{ID: 1}, nil
+		case 2:
+			return models.Category{}, failure
+		}
+		return models.Category{}, models.NewNotFoundError("category", id)
+	}
+	exists := Exists(context.Background(), lookup)
+
+	if err := exists(1); err != nil {
+		t.Errorf("Exists(1) = %v, want no error", err)
+	}
+	if err := exists(0); err != nil {
+		t.Errorf("Exists(0) = %v, want no error", err)
+	}
+	var broken *Violation
+	if err := exists(3); !errors.As(err, &broken) || broken.Code != models.FieldNotFound {
+		t.Errorf("Exists(3) = %v, want a not found violation", err)
+	}
+	if err := exists(2); !errors.Is(err, failure) {
+		t.Errorf("Exists(2) = %v, want the lookup error", err)
+	}
+}
+
+func TestExistAll(t *testing.T) {
+	lookup := func(ctx context.Context, ids []int) ([]models.Category, error) {
+		var categories []models.Category
+		for _, id := range ids {
+			if id == 1 {
+				categories = append(categories, models.Category{ID: 1})
+			}
+		}
+		return categories, nil
+	}
+	id := func(c models.Category) int { return c.ID }
+
+	exists, err := ExistAll(context.Background(), lookup, id, "category", []int{1, 3})
+	if err != nil {
+		t.Fatalf("ExistAll: %v", err)
+	}
+	if err := exists(1); err != nil {
+		t.Errorf("exists(1) = %v, want no error", err)
+	}
+	if err := exists(0); err != nil {
+		t.Errorf("exists(0) = %v, want no error", err)
+	}
+	var broken *Violation
+	if err := exists(3); !errors.As(err, &broken) || broken.Code != models.FieldNotFound || broken.Message != "category with ID 3 not found" {
+		t.Errorf("exists(3) = %v, want a not found violation", err)
+	}
+
+	failure := errors.New("connection refused")
+	failing := func(ctx context.Context, ids []int) ([]models.Category, error) { return nil, failure }
+	if _, err := ExistAll(context.Background(), failing, id, "category", []int{1}); !errors.Is(err, failure) {
+		t.Errorf("ExistAll with a failing lookup = %v, want the lookup error", err)
+	}
+}
+
+func TestValidatePaths(t *testing.T) {
+	type line struct {
+		Name string
+		IDs  []int
+	}
+	type order struct {
+		Title string
+		Lines []line
+	}
+
+	rules := Rules[order]{
+		Field("title", func(o order) string { return o.Title }, Required, MaxLength(5)),
+		Each("lines", func(o order) []line { return o.Lines }, Rules[line]{
+			Field("name", func(l line) string { return l.Name }, Required),
+			Each("ids", func(l line) []int { return l.IDs }, Rules[int]{Value(NonZero[int], Min(1))}),
+		}),
+		With(func(o order) Check[order] {
+			return Field("lines", func(o order) int { return len(o.Lines) }, Min(1))
+		}),
+	}
+
+	tests := []struct {
+		name   string
+		order  order
+		fields []string
+	}{
+		{"valid", order{Title: "Pão", Lines: []line{{Name: "a", IDs: []int{1, 2}}}}, nil},
+		{"first broken rule only", order{Title: ""}, []string{"title:required", "lines:invalid"}},
+		{"nested", order{Title: "Pão", Lines: []line{{Name: "a"}, {Name: "", IDs: []int{1, 0, -1}}}}, []string{
+			"lines[1].name:required", "lines[1].ids[1]:required", "lines[1].ids[2]:invalid",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := fieldCodes(t, rules.Validate(test.order))
+			if !slices.Equal(got, test.fields) {
+				t.Errorf("fields = %v, want %v", got, test.fields)
+			}
+		})
+	}
+}
+
+// fieldCodes lists the field errors of err as "field:code".
+func fieldCodes(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	var invalid *models.ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+
+	var fields []string
+	for _, field := range invalid.Fields {
+		fields = append(fields, field.Field+":"+field.Code)
+	}
+
+	return fields
+}